@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/samix73/game/game/components"
+	"github.com/samix73/game/game/solver"
+)
+
+var (
+	wallColor     = color.RGBA{32, 32, 40, 255}
+	floorColor    = color.RGBA{240, 240, 240, 255}
+	solutionColor = color.RGBA{220, 60, 60, 255}
+	entryColor    = color.RGBA{60, 180, 75, 255}
+	exitColor     = color.RGBA{60, 100, 220, 255}
+)
+
+// exporter writes a maze, and optionally its solution, in a single output format.
+type exporter func(w io.Writer, m components.Maze, solution []solver.Point) error
+
+var exporters = map[string]exporter{
+	"toml":  exportTOML,
+	"json":  exportJSON,
+	"ascii": exportASCII,
+	"png":   exportPNG,
+}
+
+// formatExtensions are the file extensions of the output formats.
+var formatExtensions = map[string]string{
+	"toml":  ".toml",
+	"json":  ".json",
+	"ascii": ".txt",
+	"png":   ".png",
+}
+
+// outputPath returns the file a maze is written to, "maze" with the extension of format
+// when output is empty. An output with the extension of another format is rejected,
+// so -format png never writes an image into a .toml file.
+func outputPath(output, format string) (string, error) {
+	ext, ok := formatExtensions[format]
+	if !ok {
+		return "", fmt.Errorf("unknown format %q", format)
+	}
+
+	if output == "" {
+		return "maze" + ext, nil
+	}
+
+	outputExt := strings.ToLower(filepath.Ext(output))
+	for other, otherExt := range formatExtensions {
+		if other != format && outputExt == otherExt {
+			return "", fmt.Errorf("output %q has the extension of format %s, not %s", output, other, format)
+		}
+	}
+
+	return output, nil
+}
+
+// exportTOML writes the maze as an entity file that can be referenced from a world.
+// The solution is not part of the Maze component and is ignored.
+func exportTOML(w io.Writer, m components.Maze, _ []solver.Point) error {
+	entityComponentsConfig := map[string]components.Maze{
+		"Maze": m,
+	}
+
+	if err := toml.NewEncoder(w).Encode(entityComponentsConfig); err != nil {
+		return fmt.Errorf("exportTOML: %w", err)
+	}
+
+	return nil
+}
+
+func exportJSON(w io.Writer, m components.Maze, solution []solver.Point) error {
+	// Cells are copied to ints, []uint8 would be encoded as base64.
+	cells := make([][]int, len(m.Cells))
+	for y, row := range m.Cells {
+		cells[y] = make([]int, len(row))
+		for x, cell := range row {
+			cells[y][x] = int(cell)
+		}
+	}
+
+	type jsonMaze struct {
+		Width    int
+		Height   int
+		Seed     uint64
+		CellSize float64
+		Entry    [2]int
+		Exit     [2]int
+		Cells    [][]int
+		Solution [][2]int `json:",omitempty"`
+	}
+
+	out := jsonMaze{
+		Width:    m.Width,
+		Height:   m.Height,
+		Seed:     m.Seed,
+		CellSize: m.CellSize,
+		Entry:    [2]int{int(m.Entry.X), int(m.Entry.Y)},
+		Exit:     [2]int{int(m.Exit.X), int(m.Exit.Y)},
+		Cells:    cells,
+	}
+
+	for _, p := range solution {
+		out.Solution = append(out.Solution, [2]int{p.X, p.Y})
+	}
+
+	if err := json.NewEncoder(w).Encode(out); err != nil {
+		return fmt.Errorf("exportJSON: %w", err)
+	}
+
+	return nil
+}
+
+// exportASCII draws walls as '#', the entry as 'S', the exit as 'E' and the solution as '.'.
+func exportASCII(w io.Writer, m components.Maze, solution []solver.Point) error {
+	grid := make([][]byte, m.Height)
+	for y := range grid {
+		grid[y] = []byte(strings.Repeat(" ", m.Width))
+		for x := range m.Width {
+			if m.IsWall(x, y) {
+				grid[y][x] = '#'
+			}
+		}
+	}
+
+	for _, p := range solution {
+		grid[p.Y][p.X] = '.'
+	}
+
+	grid[int(m.Entry.Y)][int(m.Entry.X)] = 'S'
+	grid[int(m.Exit.Y)][int(m.Exit.X)] = 'E'
+
+	for _, row := range grid {
+		if _, err := fmt.Fprintf(w, "%s\n", row); err != nil {
+			return fmt.Errorf("exportASCII: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// exportPNG draws every cell as a CellSize square, at least one pixel wide.
+func exportPNG(w io.Writer, m components.Maze, solution []solver.Point) error {
+	size := max(int(math.Round(m.CellSize)), 1)
+	img := image.NewRGBA(image.Rect(0, 0, m.Width*size, m.Height*size))
+
+	fill := func(x, y int, c color.RGBA) {
+		for py := y * size; py < (y+1)*size; py++ {
+			for px := x * size; px < (x+1)*size; px++ {
+				img.SetRGBA(px, py, c)
+			}
+		}
+	}
+
+	for y := range m.Height {
+		for x := range m.Width {
+			if m.IsWall(x, y) {
+				fill(x, y, wallColor)
+			} else {
+				fill(x, y, floorColor)
+			}
+		}
+	}
+
+	for _, p := range solution {
+		fill(p.X, p.Y, solutionColor)
+	}
+
+	fill(int(m.Entry.X), int(m.Entry.Y), entryColor)
+	fill(int(m.Exit.X), int(m.Exit.Y), exitColor)
+
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("exportPNG: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jakecoffman/cp"
+	"github.com/samix73/game/game/components"
+	"github.com/samix73/game/game/solver"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "Rewrite the golden files")
+
+func testMaze(t *testing.T) (components.Maze, []solver.Point) {
+	t.Helper()
+
+	m, err := components.GenerateMaze(9, 7, cp.Vector{X: 1, Y: 0}, cp.Vector{X: 7, Y: 6}, 3, 16, components.MazeOptions{})
+	require.NoError(t, err)
+
+	solution, err := solver.Solve(&m)
+	require.NoError(t, err)
+
+	return m, solution
+}
+
+func TestExportTOML_RoundTrip(t *testing.T) {
+	m, solution := testMaze(t)
+
+	var b bytes.Buffer
+	require.NoError(t, exportTOML(&b, m, solution))
+
+	var decoded map[string]components.Maze
+	_, err := toml.Decode(b.String(), &decoded)
+	require.NoError(t, err)
+	require.Equal(t, m, decoded["Maze"])
+}
+
+func TestExportJSON_RoundTrip(t *testing.T) {
+	m, solution := testMaze(t)
+
+	var b bytes.Buffer
+	require.NoError(t, exportJSON(&b, m, solution))
+
+	var decoded struct {
+		Width, Height int
+		Seed          uint64
+		CellSize      float64
+		Entry, Exit   [2]int
+		Cells         [][]uint8
+		Solution      [][2]int
+	}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &decoded))
+
+	require.Equal(t, m.Width, decoded.Width)
+	require.Equal(t, m.Height, decoded.Height)
+	require.Equal(t, m.Seed, decoded.Seed)
+	require.Equal(t, m.CellSize, decoded.CellSize)
+	require.Equal(t, [2]int{1, 0}, decoded.Entry)
+	require.Equal(t, [2]int{7, 6}, decoded.Exit)
+	require.Equal(t, m.Cells, decoded.Cells)
+
+	require.Len(t, decoded.Solution, len(solution))
+	for i, p := range solution {
+		require.Equal(t, [2]int{p.X, p.Y}, decoded.Solution[i])
+	}
+}
+
+func TestExportASCII_Golden(t *testing.T) {
+	m, solution := testMaze(t)
+
+	var b bytes.Buffer
+	require.NoError(t, exportASCII(&b, m, solution))
+
+	golden := filepath.Join("testdata", "maze.golden")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, b.Bytes(), 0o644))
+	}
+
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	require.Equal(t, string(want), b.String())
+}
+
+func TestExportPNG(t *testing.T) {
+	m, solution := testMaze(t)
+
+	var b bytes.Buffer
+	require.NoError(t, exportPNG(&b, m, solution))
+
+	img, err := png.Decode(&b)
+	require.NoError(t, err)
+	require.Equal(t, 9*16, img.Bounds().Dx())
+	require.Equal(t, 7*16, img.Bounds().Dy())
+	require.Equal(t, entryColor, img.At(16+8, 8))
+	require.Equal(t, exitColor, img.At(7*16+8, 6*16+8))
+	require.Equal(t, wallColor, img.At(8, 8))
+}
+
+func TestOutputPath(t *testing.T) {
+	tests := []struct {
+		output, format string
+		want           string
+		wantErr        bool
+	}{
+		{format: "toml", want: "maze.toml"},
+		{format: "png", want: "maze.png"},
+		{format: "ascii", want: "maze.txt"},
+		{output: "level.json", format: "json", want: "level.json"},
+		{output: "level", format: "png", want: "level"},
+		{output: "maze.toml", format: "png", wantErr: true},
+		{output: "maze.PNG", format: "json", wantErr: true},
+		{format: "svg", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output+"_"+tt.format, func(t *testing.T) {
+			got, err := outputPath(tt.output, tt.format)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -4,23 +4,33 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
+	"math"
 	"math/rand/v2"
 	"os"
 	"time"
 
-	"github.com/BurntSushi/toml"
 	"github.com/jakecoffman/cp"
 	"github.com/samix73/game/game/components"
+	"github.com/samix73/game/game/solver"
 )
 
 var (
-	output   = flag.String("output", "maze.toml", "Output file")
-	seed     = flag.Uint64("seed", rand.Uint64(), "Seed")
-	width    = flag.Int("width", 10, "Width")
-	height   = flag.Int("height", 10, "Height")
-	entry    = flag.String("entry", "0,0", "Entry point")
-	exit     = flag.String("exit", "9,9", "Exit point")
-	cellSize = flag.Float64("cell-size", 1, "Cell size")
+	output    = flag.String("output", "", "Output file, maze with the extension of -format when empty")
+	seed      = flag.Uint64("seed", rand.Uint64(), "Seed")
+	width     = flag.Int("width", 11, "Width")
+	height    = flag.Int("height", 11, "Height")
+	entry     = flag.String("entry", "1,0", "Entry point, on the edge to open the outer wall")
+	exit      = flag.String("exit", "9,10", "Exit point, on the edge to open the outer wall")
+	cellSize  = flag.Float64("cell-size", 1, "Cell size")
+	algorithm = flag.String("algorithm", string(components.MazeBacktracker), "Maze algorithm (backtracker, prim, kruskal, wilson)")
+	braid     = flag.Float64("braid", 0, "Probability of removing each dead end, between 0 and 1")
+
+	minDifficulty = flag.Float64("min-difficulty", 0, "Minimum difficulty; seeds are rerolled until the maze fits")
+	maxDifficulty = flag.Float64("max-difficulty", math.Inf(1), "Maximum difficulty; seeds are rerolled until the maze fits")
+	maxAttempts   = flag.Int("max-attempts", 1000, "Maximum number of seeds to try")
+
+	format       = flag.String("format", "toml", "Output format (toml, json, ascii, png)")
+	showSolution = flag.Bool("solution", false, "Draw the solved path in json, ascii and png output")
 )
 
 func parsePoint(point string) (cp.Vector, error) {
@@ -31,6 +41,36 @@ func parsePoint(point string) (cp.Vector, error) {
 	return cp.Vector{X: x, Y: y}, nil
 }
 
+// generate rerolls seeds, starting from -seed, until the maze is solvable and
+// its difficulty is within the requested range.
+func generate(entry, exit cp.Vector) (components.Maze, solver.Metrics, int, error) {
+	opts := components.MazeOptions{
+		Algorithm: components.MazeAlgorithm(*algorithm),
+		Braid:     *braid,
+	}
+
+	seeds := rand.New(rand.NewPCG(*seed, 0))
+	s := *seed
+
+	for attempt := 1; attempt <= *maxAttempts; attempt++ {
+		m, err := components.GenerateMaze(*width, *height, entry, exit, s, *cellSize, opts)
+		if err != nil {
+			return components.Maze{}, solver.Metrics{}, attempt, err
+		}
+
+		metrics := solver.Analyze(&m)
+		if metrics.Solvable && metrics.Connected &&
+			metrics.Difficulty >= *minDifficulty && metrics.Difficulty <= *maxDifficulty {
+			return m, metrics, attempt, nil
+		}
+
+		s = seeds.Uint64()
+	}
+
+	return components.Maze{}, solver.Metrics{}, *maxAttempts,
+		fmt.Errorf("no maze with difficulty between %.2f and %.2f after %d attempts", *minDifficulty, *maxDifficulty, *maxAttempts)
+}
+
 func main() {
 	defer func(start time.Time) {
 		fmt.Printf("Overall time: %s\n", time.Since(start))
@@ -38,8 +78,20 @@ func main() {
 
 	flag.Parse()
 
-	if *width < 2 || *height < 2 {
-		fmt.Println("Width and height must be at least 2")
+	export, ok := exporters[*format]
+	if !ok {
+		fmt.Printf("Unknown format %q\n", *format)
+		os.Exit(1)
+	}
+
+	path, err := outputPath(*output, *format)
+	if err != nil {
+		fmt.Println("Invalid output:", err)
+		os.Exit(1)
+	}
+
+	if *width < 3 || *height < 3 {
+		fmt.Println("Width and height must be at least 3")
 		os.Exit(1)
 	}
 
@@ -54,21 +106,32 @@ func main() {
 		os.Exit(1)
 	}
 
-	m, err := components.GenerateMaze(*width, *height, entry, exit, *seed, *cellSize)
+	m, metrics, attempts, err := generate(entry, exit)
 	if err != nil {
-		fmt.Println("Invalid seed")
+		fmt.Println("Error generating maze:", err)
 		os.Exit(1)
 	}
 
-	entityComponentsConfig := map[string]components.Maze{
-		"Maze": m,
+	fmt.Printf("Seed: %d (attempt %d)\n", m.Seed, attempts)
+	fmt.Print(metrics)
+
+	var solution []solver.Point
+	if *showSolution {
+		solution, err = solver.Solve(&m)
+		if err != nil {
+			fmt.Println("Error solving maze:", err)
+			os.Exit(1)
+		}
 	}
 
 	b := new(bytes.Buffer)
-	enc := toml.NewEncoder(b)
-	if err := enc.Encode(entityComponentsConfig); err != nil {
-		panic(err)
+	if err := export(b, m, solution); err != nil {
+		fmt.Println("Error exporting maze:", err)
+		os.Exit(1)
 	}
 
-	os.WriteFile(*output, b.Bytes(), os.ModePerm)
+	if err := os.WriteFile(path, b.Bytes(), os.ModePerm); err != nil {
+		fmt.Println("Error writing maze:", err)
+		os.Exit(1)
+	}
 }
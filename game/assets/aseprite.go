@@ -0,0 +1,298 @@
+package assets
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Directions of Aseprite frame tags.
+const (
+	AsepriteForward         = "forward"
+	AsepriteReverse         = "reverse"
+	AsepritePingPong        = "pingpong"
+	AsepritePingPongReverse = "pingpong_reverse"
+)
+
+// AsepriteSheet is a sprite sheet exported from Aseprite with its JSON data,
+// in either the "Hash" or the "Array" layout. Frames must not be rotated or trimmed.
+type AsepriteSheet struct {
+	// Image is the path of the sheet image in SpritesDir.
+	Image  string
+	Frames []AsepriteFrame
+	Tags   []AsepriteTag
+	Slices []AsepriteSlice
+}
+
+// AsepriteFrame is a frame of the sheet.
+type AsepriteFrame struct {
+	// Bounds is where the frame is in the sheet image.
+	Bounds   image.Rectangle
+	Duration time.Duration
+	// SourceSize is the size of the sprite canvas, which slices are relative to.
+	SourceSize image.Point
+}
+
+// AsepriteTag is a named range of frames, an animation in Aseprite.
+type AsepriteTag struct {
+	Name      string
+	From, To  int
+	Direction string
+	// Repeat is the number of times the animation plays, forever when zero.
+	Repeat int
+}
+
+// AsepriteSlice is a named area of the sprite canvas, such as a hitbox.
+type AsepriteSlice struct {
+	Name string
+	Keys []AsepriteSliceKey
+}
+
+// AsepriteSliceKey is the area of a slice from Frame on.
+type AsepriteSliceKey struct {
+	Frame  int
+	Bounds image.Rectangle
+}
+
+type asepriteRect struct {
+	X, Y, W, H int
+}
+
+func (r asepriteRect) rectangle() image.Rectangle {
+	return image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H)
+}
+
+type asepriteFrameData struct {
+	Frame            asepriteRect  `json:"frame"`
+	Rotated          bool          `json:"rotated"`
+	Trimmed          bool          `json:"trimmed"`
+	SpriteSourceSize *asepriteRect `json:"spriteSourceSize"`
+	SourceSize       asepriteRect  `json:"sourceSize"`
+	Duration         int           `json:"duration"`
+}
+
+// trimmed reports whether the frame was cut down to its opaque pixels, which moves
+// the sprite within the frame.
+func (f asepriteFrameData) trimmed() bool {
+	if f.Trimmed {
+		return true
+	}
+
+	if f.SpriteSourceSize == nil {
+		return false
+	}
+
+	return f.SpriteSourceSize.X != 0 || f.SpriteSourceSize.Y != 0 ||
+		f.SpriteSourceSize.W != f.SourceSize.W || f.SpriteSourceSize.H != f.SourceSize.H
+}
+
+type asepriteData struct {
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		Image     string `json:"image"`
+		FrameTags []struct {
+			Name      string `json:"name"`
+			From      int    `json:"from"`
+			To        int    `json:"to"`
+			Direction string `json:"direction"`
+			Repeat    string `json:"repeat"`
+		} `json:"frameTags"`
+		Slices []struct {
+			Name string `json:"name"`
+			Keys []struct {
+				Frame  int          `json:"frame"`
+				Bounds asepriteRect `json:"bounds"`
+			} `json:"keys"`
+		} `json:"slices"`
+	} `json:"meta"`
+}
+
+// decodeAsepriteFrames decodes the frames of either layout. The frame order of the "Hash"
+// layout is the order of the keys in the file, so its object is read token by token.
+func decodeAsepriteFrames(data json.RawMessage) ([]asepriteFrameData, error) {
+	frames := make([]asepriteFrameData, 0)
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &frames); err != nil {
+			return nil, err
+		}
+
+		return frames, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return nil, errors.New("frames is neither an object nor an array")
+	}
+
+	for dec.More() {
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+
+		var frame asepriteFrameData
+		if err := dec.Decode(&frame); err != nil {
+			return nil, err
+		}
+
+		frames = append(frames, frame)
+	}
+
+	return frames, nil
+}
+
+// ParseAseprite parses the JSON data of an Aseprite sprite sheet.
+// The sheet Image is resolved relative to dir.
+func ParseAseprite(data []byte, dir string) (*AsepriteSheet, error) {
+	var raw asepriteData
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("assets.ParseAseprite: %w", err)
+	}
+
+	frames, err := decodeAsepriteFrames(raw.Frames)
+	if err != nil {
+		return nil, fmt.Errorf("assets.ParseAseprite: %w", err)
+	}
+
+	sheet := &AsepriteSheet{
+		Image:  path.Join(dir, raw.Meta.Image),
+		Frames: make([]AsepriteFrame, 0, len(frames)),
+		Tags:   make([]AsepriteTag, 0, len(raw.Meta.FrameTags)),
+		Slices: make([]AsepriteSlice, 0, len(raw.Meta.Slices)),
+	}
+
+	for i, frame := range frames {
+		if frame.Rotated {
+			return nil, fmt.Errorf("assets.ParseAseprite: frame %d is rotated, export without rotation", i)
+		}
+
+		if frame.trimmed() {
+			return nil, fmt.Errorf("assets.ParseAseprite: frame %d is trimmed, export without trimming", i)
+		}
+
+		sheet.Frames = append(sheet.Frames, AsepriteFrame{
+			Bounds:     frame.Frame.rectangle(),
+			Duration:   time.Duration(frame.Duration) * time.Millisecond,
+			SourceSize: image.Pt(frame.SourceSize.W, frame.SourceSize.H),
+		})
+	}
+
+	for _, tag := range raw.Meta.FrameTags {
+		if tag.From < 0 || tag.To >= len(sheet.Frames) || tag.From > tag.To {
+			return nil, fmt.Errorf("assets.ParseAseprite: tag %q has frames %d to %d out of %d", tag.Name, tag.From, tag.To, len(sheet.Frames))
+		}
+
+		var repeat int
+		if tag.Repeat != "" {
+			if repeat, err = strconv.Atoi(tag.Repeat); err != nil {
+				return nil, fmt.Errorf("assets.ParseAseprite: tag %q repeat: %w", tag.Name, err)
+			}
+		}
+
+		direction := tag.Direction
+		if direction == "" {
+			direction = AsepriteForward
+		}
+
+		sheet.Tags = append(sheet.Tags, AsepriteTag{
+			Name:      tag.Name,
+			From:      tag.From,
+			To:        tag.To,
+			Direction: direction,
+			Repeat:    repeat,
+		})
+	}
+
+	for _, slice := range raw.Meta.Slices {
+		keys := make([]AsepriteSliceKey, 0, len(slice.Keys))
+		for _, key := range slice.Keys {
+			keys = append(keys, AsepriteSliceKey{Frame: key.Frame, Bounds: key.Bounds.rectangle()})
+		}
+
+		sheet.Slices = append(sheet.Slices, AsepriteSlice{Name: slice.Name, Keys: keys})
+	}
+
+	return sheet, nil
+}
+
+// Slice returns the bounds of the named slice on a frame, relative to the sprite canvas.
+func (s *AsepriteSheet) Slice(name string, frame int) (image.Rectangle, bool) {
+	for _, slice := range s.Slices {
+		if slice.Name != name {
+			continue
+		}
+
+		// A key applies from its frame until the next key.
+		found := false
+		var bounds image.Rectangle
+		for _, key := range slice.Keys {
+			if key.Frame <= frame || !found {
+				bounds, found = key.Bounds, true
+			}
+		}
+
+		return bounds, found
+	}
+
+	return image.Rectangle{}, false
+}
+
+// Frame returns the sprite of a frame, drawn from the sheet image.
+func (s *AsepriteSheet) Frame(frame int) (*ebiten.Image, error) {
+	if frame < 0 || frame >= len(s.Frames) {
+		return nil, fmt.Errorf("assets.AsepriteSheet.Frame: frame %d out of %d", frame, len(s.Frames))
+	}
+
+	sheet, err := GetSprite(s.Image)
+	if err != nil {
+		return nil, fmt.Errorf("assets.AsepriteSheet.Frame: %w", err)
+	}
+
+	return SubSprite(sheet, s.Frames[frame].Bounds), nil
+}
+
+// IsAseprite reports whether a sprite path is an Aseprite JSON sheet.
+func IsAseprite(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".json")
+}
+
+var asepriteSheets = struct {
+	sync.Mutex
+	sheets map[string]*AsepriteSheet
+}{sheets: make(map[string]*AsepriteSheet)}
+
+// GetAseprite loads an Aseprite JSON sheet from SpritesDir.
+// Sheets are loaded once, later calls return the same sheet.
+func GetAseprite(name string) (*AsepriteSheet, error) {
+	asepriteSheets.Lock()
+	defer asepriteSheets.Unlock()
+
+	if sheet, ok := asepriteSheets.sheets[name]; ok {
+		return sheet, nil
+	}
+
+	data, err := os.ReadFile(path.Join(SpritesDir, name))
+	if err != nil {
+		return nil, fmt.Errorf("assets.GetAseprite: %w", err)
+	}
+
+	sheet, err := ParseAseprite(data, path.Dir(name))
+	if err != nil {
+		return nil, fmt.Errorf("assets.GetAseprite: %w", err)
+	}
+
+	asepriteSheets.sheets[name] = sheet
+
+	return sheet, nil
+}
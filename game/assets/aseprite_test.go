@@ -0,0 +1,94 @@
+package assets
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const asepriteHash = `{
+	"frames": {
+		"walk 2.aseprite": {"frame": {"x": 32, "y": 0, "w": 16, "h": 16}, "rotated": false, "sourceSize": {"w": 16, "h": 16}, "duration": 80},
+		"walk 0.aseprite": {"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "rotated": false, "sourceSize": {"w": 16, "h": 16}, "duration": 100},
+		"walk 1.aseprite": {"frame": {"x": 16, "y": 0, "w": 16, "h": 16}, "rotated": false, "sourceSize": {"w": 16, "h": 16}, "duration": 120}
+	},
+	"meta": {
+		"image": "walk.png",
+		"frameTags": [
+			{"name": "walk", "from": 0, "to": 2, "direction": "pingpong"},
+			{"name": "jump", "from": 1, "to": 2, "direction": "forward", "repeat": "3"}
+		],
+		"slices": [
+			{"name": "hitbox", "keys": [
+				{"frame": 0, "bounds": {"x": 2, "y": 4, "w": 12, "h": 12}},
+				{"frame": 2, "bounds": {"x": 3, "y": 5, "w": 10, "h": 11}}
+			]}
+		]
+	}
+}`
+
+func TestParseAseprite_Hash(t *testing.T) {
+	sheet, err := ParseAseprite([]byte(asepriteHash), "characters")
+	require.NoError(t, err)
+
+	require.Equal(t, "characters/walk.png", sheet.Image)
+
+	// Frames keep the order of the file, not the order of their names.
+	require.Len(t, sheet.Frames, 3)
+	require.Equal(t, image.Rect(32, 0, 48, 16), sheet.Frames[0].Bounds)
+	require.Equal(t, 80*time.Millisecond, sheet.Frames[0].Duration)
+	require.Equal(t, image.Rect(16, 0, 32, 16), sheet.Frames[2].Bounds)
+	require.Equal(t, image.Pt(16, 16), sheet.Frames[2].SourceSize)
+
+	require.Equal(t, []AsepriteTag{
+		{Name: "walk", From: 0, To: 2, Direction: AsepritePingPong},
+		{Name: "jump", From: 1, To: 2, Direction: AsepriteForward, Repeat: 3},
+	}, sheet.Tags)
+
+	bounds, ok := sheet.Slice("hitbox", 1)
+	require.True(t, ok)
+	require.Equal(t, image.Rect(2, 4, 14, 16), bounds)
+
+	bounds, ok = sheet.Slice("hitbox", 2)
+	require.True(t, ok)
+	require.Equal(t, image.Rect(3, 5, 13, 16), bounds)
+
+	_, ok = sheet.Slice("hurtbox", 0)
+	require.False(t, ok)
+}
+
+func TestParseAseprite_Array(t *testing.T) {
+	sheet, err := ParseAseprite([]byte(`{
+		"frames": [
+			{"filename": "0", "frame": {"x": 0, "y": 0, "w": 8, "h": 8}, "duration": 50},
+			{"filename": "1", "frame": {"x": 0, "y": 8, "w": 8, "h": 8}, "duration": 60}
+		],
+		"meta": {"image": "blink.png"}
+	}`), ".")
+	require.NoError(t, err)
+
+	require.Equal(t, "blink.png", sheet.Image)
+	require.Len(t, sheet.Frames, 2)
+	require.Equal(t, image.Rect(0, 8, 8, 16), sheet.Frames[1].Bounds)
+	require.Equal(t, 60*time.Millisecond, sheet.Frames[1].Duration)
+	require.Empty(t, sheet.Tags)
+}
+
+func TestParseAseprite_Errors(t *testing.T) {
+	_, err := ParseAseprite([]byte(`{"frames": [{"frame": {"w": 8, "h": 8}, "rotated": true}], "meta": {}}`), ".")
+	require.ErrorContains(t, err, "rotated")
+
+	_, err = ParseAseprite([]byte(`{"frames": [{"frame": {"w": 6, "h": 8}, "trimmed": true, "spriteSourceSize": {"x": 1, "w": 6, "h": 8}, "sourceSize": {"w": 8, "h": 8}}], "meta": {}}`), ".")
+	require.ErrorContains(t, err, "trimmed")
+
+	_, err = ParseAseprite([]byte(`{"frames": [{"frame": {"w": 6, "h": 8}, "spriteSourceSize": {"x": 1, "w": 6, "h": 8}, "sourceSize": {"w": 8, "h": 8}}], "meta": {}}`), ".")
+	require.ErrorContains(t, err, "trimmed")
+
+	_, err = ParseAseprite([]byte(`{"frames": [{"frame": {"w": 8, "h": 8}}], "meta": {"frameTags": [{"name": "run", "from": 0, "to": 3}]}}`), ".")
+	require.ErrorContains(t, err, "run")
+
+	_, err = ParseAseprite([]byte(`{"frames": 3, "meta": {}}`), ".")
+	require.Error(t, err)
+}
@@ -17,7 +17,23 @@ const (
 	EntitiesDir = "game/assets/Entities"
 )
 
+// GetSprite loads a sprite from SpritesDir into the sprite atlas.
+// Sprites are loaded once, later calls return the same image.
+// The sprite of an Aseprite JSON sheet is its first frame.
 func GetSprite(name string) (*ebiten.Image, error) {
+	if IsAseprite(name) {
+		sheet, err := GetAseprite(name)
+		if err != nil {
+			return nil, fmt.Errorf("assets.GetSprite: %w", err)
+		}
+
+		return sheet.Frame(0)
+	}
+
+	if sprite, ok := sprites.Sprite(name); ok {
+		return sprite, nil
+	}
+
 	path := path.Join(SpritesDir, name)
 
 	data, err := os.ReadFile(path)
@@ -30,7 +46,7 @@ func GetSprite(name string) (*ebiten.Image, error) {
 		return nil, fmt.Errorf("assets.GetSprite: %w", err)
 	}
 
-	return ebiten.NewImageFromImage(img), nil
+	return sprites.Add(name, img), nil
 }
 
 func GetWorld(name string) ([]byte, error) {
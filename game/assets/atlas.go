@@ -0,0 +1,208 @@
+package assets
+
+import (
+	"image"
+	"image/draw"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// DefaultAtlasSize is the width and height of the pages of the sprite atlas.
+	DefaultAtlasSize = 2048
+	// atlasPadding is the transparent border kept around packed sprites,
+	// so filtering never samples a neighbouring sprite.
+	atlasPadding = 1
+)
+
+// shelfPacker places rectangles left to right on shelves, starting a new shelf
+// above the tallest rectangle of the current one when it is full.
+type shelfPacker struct {
+	size        int
+	x, y        int
+	shelfHeight int
+}
+
+// pack returns the top-left corner of a free width by height area, with padding around it.
+// It returns false when the page has no room left for it.
+func (p *shelfPacker) pack(width, height int) (image.Point, bool) {
+	width += 2 * atlasPadding
+	height += 2 * atlasPadding
+
+	if width > p.size || height > p.size {
+		return image.Point{}, false
+	}
+
+	if p.x+width > p.size {
+		p.x = 0
+		p.y += p.shelfHeight
+		p.shelfHeight = 0
+	}
+
+	if p.y+height > p.size {
+		return image.Point{}, false
+	}
+
+	origin := image.Pt(p.x+atlasPadding, p.y+atlasPadding)
+	p.x += width
+	p.shelfHeight = max(p.shelfHeight, height)
+
+	return origin, true
+}
+
+type atlasPage struct {
+	image  *ebiten.Image
+	packer shelfPacker
+}
+
+// Region is the area of an atlas page a sprite is drawn from.
+type Region struct {
+	Page   *ebiten.Image
+	Bounds image.Rectangle
+}
+
+// Atlas packs sprites into shared pages, so sprites of the same page can be drawn
+// together with a single DrawTriangles call.
+type Atlas struct {
+	mu sync.Mutex
+
+	pageSize int
+	pages    []*atlasPage
+	sprites  map[string]*ebiten.Image
+	regions  map[*ebiten.Image]Region
+	// subs are the sub-sprites by the area of the page they are drawn from, so asking for
+	// the same area through any sprite returns the same image and the cache never grows
+	// past the distinct areas of the pages.
+	subs map[Region]*ebiten.Image
+}
+
+func NewAtlas(pageSize int) *Atlas {
+	return &Atlas{
+		pageSize: pageSize,
+		pages:    make([]*atlasPage, 0),
+		sprites:  make(map[string]*ebiten.Image),
+		regions:  make(map[*ebiten.Image]Region),
+		subs:     make(map[Region]*ebiten.Image),
+	}
+}
+
+// place finds room for a sprite of the given size, adding a page when every page is full.
+// Sprites larger than a page get a page of their own.
+func (a *Atlas) place(size image.Point) (*atlasPage, image.Point) {
+	for _, page := range a.pages {
+		if origin, ok := page.packer.pack(size.X, size.Y); ok {
+			return page, origin
+		}
+	}
+
+	pageSize := max(a.pageSize, size.X+2*atlasPadding, size.Y+2*atlasPadding)
+	page := &atlasPage{
+		image:  ebiten.NewImage(pageSize, pageSize),
+		packer: shelfPacker{size: pageSize},
+	}
+	a.pages = append(a.pages, page)
+
+	origin, _ := page.packer.pack(size.X, size.Y)
+
+	return page, origin
+}
+
+// Add packs img into the atlas under name and returns the sprite, a sub-image of its page.
+// Adding a name that is already packed returns the existing sprite.
+func (a *Atlas) Add(name string, img image.Image) *ebiten.Image {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if sprite, ok := a.sprites[name]; ok {
+		return sprite
+	}
+
+	size := img.Bounds().Size()
+	page, origin := a.place(size)
+	bounds := image.Rectangle{Min: origin, Max: origin.Add(size)}
+
+	// image.RGBA holds premultiplied alpha, as WritePixels expects.
+	rgba := image.NewRGBA(image.Rectangle{Max: size})
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	sprite := page.image.SubImage(bounds).(*ebiten.Image)
+	sprite.WritePixels(rgba.Pix)
+
+	a.sprites[name] = sprite
+	a.regions[sprite] = Region{Page: page.image, Bounds: bounds}
+
+	return sprite
+}
+
+// Sprite returns the sprite packed under name.
+func (a *Atlas) Sprite(name string) (*ebiten.Image, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	sprite, ok := a.sprites[name]
+	return sprite, ok
+}
+
+// Region returns the page and bounds a sprite of this atlas is drawn from.
+func (a *Atlas) Region(sprite *ebiten.Image) (Region, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	region, ok := a.regions[sprite]
+	return region, ok
+}
+
+// SubSprite returns the part of a packed sprite within bounds, relative to the sprite's
+// top-left corner, such as a frame of a sprite sheet. The sub-sprite is drawn from the
+// same page and the same area of a page always returns the same image.
+func (a *Atlas) SubSprite(sprite *ebiten.Image, bounds image.Rectangle) (*ebiten.Image, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	region, ok := a.regions[sprite]
+	if !ok {
+		return nil, false
+	}
+
+	key := Region{Page: region.Page, Bounds: bounds.Add(region.Bounds.Min).Intersect(region.Bounds)}
+	if sub, ok := a.subs[key]; ok {
+		return sub, true
+	}
+
+	sub := region.Page.SubImage(key.Bounds).(*ebiten.Image)
+
+	a.subs[key] = sub
+	a.regions[sub] = key
+
+	return sub, true
+}
+
+// sprites is the atlas holding every sprite loaded through this package.
+var sprites = NewAtlas(DefaultAtlasSize)
+
+// AddSprite packs a generated image into the sprite atlas under name,
+// adding a name that is already packed returns the existing sprite.
+func AddSprite(name string, img image.Image) *ebiten.Image {
+	return sprites.Add(name, img)
+}
+
+// SpriteRegion returns the atlas page and bounds of a sprite.
+// Sprites that are not packed are their own page.
+func SpriteRegion(sprite *ebiten.Image) (Region, bool) {
+	if region, ok := sprites.Region(sprite); ok {
+		return region, true
+	}
+
+	return Region{Page: sprite, Bounds: sprite.Bounds()}, false
+}
+
+// SubSprite returns the part of a sprite within bounds, relative to the sprite's top-left corner.
+// Parts of sprites packed in the sprite atlas are cached and batched with their page.
+func SubSprite(sprite *ebiten.Image, bounds image.Rectangle) *ebiten.Image {
+	if sub, ok := sprites.SubSprite(sprite, bounds); ok {
+		return sub
+	}
+
+	return sprite.SubImage(bounds.Add(sprite.Bounds().Min)).(*ebiten.Image)
+}
@@ -0,0 +1,61 @@
+package assets
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShelfPacker_Pack(t *testing.T) {
+	p := shelfPacker{size: 64}
+
+	origin, ok := p.pack(30, 10)
+	require.True(t, ok)
+	require.Equal(t, image.Pt(1, 1), origin)
+
+	origin, ok = p.pack(30, 20)
+	require.True(t, ok)
+	require.Equal(t, image.Pt(33, 1), origin)
+
+	// The shelf is full, the next sprite starts a shelf above the tallest one.
+	origin, ok = p.pack(10, 10)
+	require.True(t, ok)
+	require.Equal(t, image.Pt(1, 23), origin)
+
+	_, ok = p.pack(10, 41)
+	require.False(t, ok)
+
+	_, ok = p.pack(64, 1)
+	require.False(t, ok)
+}
+
+func TestAtlas_SubSprite(t *testing.T) {
+	a := NewAtlas(64)
+	sheet := a.Add("sheet", image.NewRGBA(image.Rect(0, 0, 32, 16)))
+	region, ok := a.Region(sheet)
+	require.True(t, ok)
+
+	frame, ok := a.SubSprite(sheet, image.Rect(16, 0, 32, 16))
+	require.True(t, ok)
+	frameRegion, ok := a.Region(frame)
+	require.True(t, ok)
+	require.Equal(t, Region{Page: region.Page, Bounds: image.Rect(17, 1, 33, 17)}, frameRegion)
+
+	// The same area of the page is the same image, whichever sprite it is cut from.
+	again, ok := a.SubSprite(frame, image.Rect(0, 0, 16, 16))
+	require.True(t, ok)
+	require.Same(t, frame, again)
+
+	// Cutting other areas never drops the images handed out before.
+	for x := range 16 {
+		_, ok := a.SubSprite(sheet, image.Rect(x, 0, x+1, 1))
+		require.True(t, ok)
+	}
+	_, ok = a.Region(frame)
+	require.True(t, ok)
+
+	_, ok = a.SubSprite(ebiten.NewImage(4, 4), image.Rect(0, 0, 2, 2))
+	require.False(t, ok)
+}
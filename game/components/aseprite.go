@@ -0,0 +1,178 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jakecoffman/cp"
+	"github.com/samix73/game/game/assets"
+)
+
+// asepriteDurations returns the frame durations of sheet frames, in seconds.
+func asepriteDurations(sheet *assets.AsepriteSheet, frames []int) []float64 {
+	durations := make([]float64, len(frames))
+	for i, frame := range frames {
+		if frame >= 0 && frame < len(sheet.Frames) {
+			durations[i] = sheet.Frames[frame].Duration.Seconds()
+		}
+	}
+
+	return durations
+}
+
+// asepriteClip converts a frame tag into a clip. Tags that repeat forever loop or ping-pong,
+// tags with a repeat count play their passes once, each ping-pong pass turning around
+// on the frame the previous one ended on.
+func asepriteClip(sheet *assets.AsepriteSheet, tag assets.AsepriteTag) AnimationClip {
+	forward := make([]int, 0, tag.To-tag.From+1)
+	for frame := tag.From; frame <= tag.To; frame++ {
+		forward = append(forward, frame)
+	}
+
+	backward := slices.Clone(forward)
+	slices.Reverse(backward)
+
+	pingPong := tag.Direction == assets.AsepritePingPong || tag.Direction == assets.AsepritePingPongReverse
+	if tag.Direction == assets.AsepriteReverse || tag.Direction == assets.AsepritePingPongReverse {
+		forward, backward = backward, forward
+	}
+
+	clip := AnimationClip{Name: tag.Name, Frames: forward}
+
+	switch {
+	case tag.Repeat > 0:
+		clip.Mode = AnimationOnce
+		clip.Frames = slices.Clone(forward)
+
+		for pass := 1; pass < tag.Repeat; pass++ {
+			if !pingPong {
+				clip.Frames = append(clip.Frames, forward...)
+			} else if pass%2 == 1 {
+				clip.Frames = append(clip.Frames, backward[1:]...)
+			} else {
+				clip.Frames = append(clip.Frames, forward[1:]...)
+			}
+		}
+	case pingPong:
+		clip.Mode = AnimationPingPong
+	default:
+		clip.Mode = AnimationLoop
+	}
+
+	clip.Durations = asepriteDurations(sheet, clip.Frames)
+
+	return clip
+}
+
+// AsepriteClips returns a clip for each frame tag of an Aseprite sheet,
+// or a single looping clip of every frame when the sheet has no tags.
+func AsepriteClips(sheet *assets.AsepriteSheet) []AnimationClip {
+	if len(sheet.Tags) == 0 {
+		if len(sheet.Frames) == 0 {
+			return nil
+		}
+
+		return []AnimationClip{asepriteClip(sheet, assets.AsepriteTag{To: len(sheet.Frames) - 1})}
+	}
+
+	clips := make([]AnimationClip, 0, len(sheet.Tags))
+	for _, tag := range sheet.Tags {
+		clips = append(clips, asepriteClip(sheet, tag))
+	}
+
+	return clips
+}
+
+// LoadAseprite sets the clips of the animation from the frame tags of an Aseprite sheet when it
+// declares none, and the frame durations of declared clips without Durations.
+// Loading the same sheet again changes nothing.
+func (a *SpriteAnimation) LoadAseprite(sheet *assets.AsepriteSheet) {
+	if len(a.Clips) == 0 {
+		a.Clips = AsepriteClips(sheet)
+		return
+	}
+
+	for i := range a.Clips {
+		if len(a.Clips[i].Durations) == 0 && a.Clips[i].FPS <= 0 {
+			a.Clips[i].Durations = asepriteDurations(sheet, a.Clips[i].Frames)
+		}
+	}
+}
+
+// SpriteSlice is a slice of an Aseprite sheet, such as a hitbox drawn by the artist.
+// Entity TOML refers to it as "sheet.json#slice". Decoding only reads the reference,
+// SpriteSliceSystem loads the sheet and sets Bounds.
+type SpriteSlice struct {
+	Sheet string
+	Name  string
+	// Bounds are the bounds of the slice on the first frame, relative to the center of
+	// the sprite with y pointing up, like the bounds of a Collider.
+	Bounds cp.BB
+	loaded bool
+}
+
+// IsZero reports whether the slice refers to nothing.
+func (s SpriteSlice) IsZero() bool {
+	return s.Sheet == "" && s.Name == ""
+}
+
+// Loaded reports whether Bounds were set from the sheet.
+func (s SpriteSlice) Loaded() bool {
+	return s.loaded
+}
+
+// Load sets Bounds from the slice of sheet, the sheet at Sheet.
+func (s *SpriteSlice) Load(sheet *assets.AsepriteSheet) error {
+	bounds, ok := sliceBounds(sheet, s.Name)
+	if !ok {
+		return fmt.Errorf("components.SpriteSlice.Load: %s has no slice %q", s.Sheet, s.Name)
+	}
+
+	s.Bounds = bounds
+	s.loaded = true
+
+	return nil
+}
+
+func (s SpriteSlice) MarshalText() ([]byte, error) {
+	if s.IsZero() {
+		return nil, nil
+	}
+
+	return []byte(s.Sheet + "#" + s.Name), nil
+}
+
+func (s *SpriteSlice) UnmarshalText(text []byte) error {
+	sheet, name, ok := strings.Cut(string(text), "#")
+	if !ok || sheet == "" || name == "" {
+		return fmt.Errorf("components.SpriteSlice.UnmarshalText: %q is not sheet.json#slice", text)
+	}
+
+	*s = SpriteSlice{Sheet: sheet, Name: name}
+
+	return nil
+}
+
+// sliceBounds returns the bounds of a slice on the first frame of sheet,
+// converted from canvas pixels to bounds around the sprite's center.
+func sliceBounds(sheet *assets.AsepriteSheet, name string) (cp.BB, bool) {
+	rect, ok := sheet.Slice(name, 0)
+	if !ok || len(sheet.Frames) == 0 {
+		return cp.BB{}, false
+	}
+
+	canvas := sheet.Frames[0].SourceSize
+	if canvas.X == 0 || canvas.Y == 0 {
+		canvas = sheet.Frames[0].Bounds.Size()
+	}
+
+	hw, hh := float64(canvas.X)/2, float64(canvas.Y)/2
+
+	return cp.BB{
+		L: float64(rect.Min.X) - hw,
+		B: hh - float64(rect.Max.Y),
+		R: float64(rect.Max.X) - hw,
+		T: hh - float64(rect.Min.Y),
+	}, true
+}
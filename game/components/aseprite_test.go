@@ -0,0 +1,108 @@
+package components
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/jakecoffman/cp"
+	"github.com/samix73/game/game/assets"
+	"github.com/stretchr/testify/require"
+)
+
+func testAsepriteSheet(tags ...assets.AsepriteTag) *assets.AsepriteSheet {
+	frames := make([]assets.AsepriteFrame, 0, 4)
+	for i := range 4 {
+		frames = append(frames, assets.AsepriteFrame{
+			Bounds:     image.Rect(i*32, 0, (i+1)*32, 32),
+			Duration:   time.Duration(i+1) * 100 * time.Millisecond,
+			SourceSize: image.Pt(32, 32),
+		})
+	}
+
+	return &assets.AsepriteSheet{Frames: frames, Tags: tags}
+}
+
+func TestAsepriteClips(t *testing.T) {
+	clips := AsepriteClips(testAsepriteSheet(
+		assets.AsepriteTag{Name: "run", From: 1, To: 3, Direction: assets.AsepriteReverse},
+		assets.AsepriteTag{Name: "idle", From: 0, To: 1, Direction: assets.AsepritePingPong},
+		assets.AsepriteTag{Name: "jump", From: 0, To: 2, Direction: assets.AsepritePingPong, Repeat: 3},
+	))
+
+	require.Equal(t, []AnimationClip{
+		{Name: "run", Frames: []int{3, 2, 1}, Durations: []float64{0.4, 0.3, 0.2}, Mode: AnimationLoop},
+		{Name: "idle", Frames: []int{0, 1}, Durations: []float64{0.1, 0.2}, Mode: AnimationPingPong},
+		{Name: "jump", Frames: []int{0, 1, 2, 1, 0, 1, 2}, Durations: []float64{0.1, 0.2, 0.3, 0.2, 0.1, 0.2, 0.3}, Mode: AnimationOnce},
+	}, clips)
+
+	// Sheets without tags play every frame.
+	clips = AsepriteClips(testAsepriteSheet())
+	require.Len(t, clips, 1)
+	require.Equal(t, []int{0, 1, 2, 3}, clips[0].Frames)
+}
+
+func TestSpriteAnimation_Durations(t *testing.T) {
+	a := &SpriteAnimation{}
+	a.LoadAseprite(testAsepriteSheet(assets.AsepriteTag{Name: "walk", From: 0, To: 2}))
+
+	// Each frame is shown for its own duration.
+	a.Advance(0.09)
+	frame, _ := a.SheetFrame()
+	require.Equal(t, 0, frame)
+
+	a.Advance(0.02)
+	frame, _ = a.SheetFrame()
+	require.Equal(t, 1, frame)
+
+	a.Advance(0.2)
+	frame, _ = a.SheetFrame()
+	require.Equal(t, 2, frame)
+
+	// Loading again keeps the clips and their progress.
+	a.LoadAseprite(testAsepriteSheet())
+	require.Equal(t, "walk", a.Clips[0].Name)
+	require.Equal(t, 2, a.Frame)
+}
+
+func TestSliceBounds(t *testing.T) {
+	sheet := testAsepriteSheet()
+	sheet.Slices = []assets.AsepriteSlice{{
+		Name: "body",
+		Keys: []assets.AsepriteSliceKey{{Frame: 0, Bounds: image.Rect(4, 8, 28, 32)}},
+	}}
+
+	bounds, ok := sliceBounds(sheet, "body")
+	require.True(t, ok)
+	require.Equal(t, cp.BB{L: -12, B: -16, R: 12, T: 8}, bounds)
+
+	_, ok = sliceBounds(sheet, "head")
+	require.False(t, ok)
+}
+
+func TestSpriteSlice(t *testing.T) {
+	var slice SpriteSlice
+	require.Error(t, slice.UnmarshalText([]byte("sheet.json")))
+
+	// Decoding only reads the reference, the sheet is not loaded.
+	require.NoError(t, slice.UnmarshalText([]byte("missing.json#body")))
+	require.Equal(t, "missing.json", slice.Sheet)
+	require.Equal(t, "body", slice.Name)
+	require.False(t, slice.Loaded())
+
+	collider := Collider{Slice: slice}
+	require.Equal(t, cp.BB{}, collider.LocalBounds())
+
+	sheet := testAsepriteSheet()
+	sheet.Slices = []assets.AsepriteSlice{{
+		Name: "body",
+		Keys: []assets.AsepriteSliceKey{{Frame: 0, Bounds: image.Rect(4, 8, 28, 32)}},
+	}}
+
+	require.NoError(t, collider.Slice.Load(sheet))
+	require.True(t, collider.Slice.Loaded())
+	require.Equal(t, cp.BB{L: -12, B: -16, R: 12, T: 8}, collider.LocalBounds())
+
+	collider.Slice.Name = "head"
+	require.ErrorContains(t, collider.Slice.Load(sheet), "head")
+}
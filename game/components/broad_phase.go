@@ -0,0 +1,36 @@
+package components
+
+import (
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+func init() {
+	ecs.RegisterComponent[BroadPhase]()
+}
+
+// SpatialIndex finds colliders by their world bounds.
+type SpatialIndex interface {
+	// Query calls fn for every collider whose bounds may overlap bounds, once each.
+	Query(bounds cp.BB, fn func(entity ecs.EntityID, bounds cp.BB))
+	// Cells calls fn with the bounds of every occupied cell of the index.
+	Cells(fn func(bounds cp.BB))
+}
+
+var _ ecs.Component = (*BroadPhase)(nil)
+
+// BroadPhase is a singleton that shares the broad phase of a world's collision detection
+// with the systems that query colliders. CollisionSystem adds it when it starts and
+// removes it on teardown, a world has at most one.
+type BroadPhase struct {
+	// Index holds the colliders where the last collision update found them.
+	Index SpatialIndex
+}
+
+func (b *BroadPhase) Init() {
+	b.Index = nil
+}
+
+func (b *BroadPhase) Reset() {
+	b.Index = nil
+}
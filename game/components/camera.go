@@ -1,6 +1,11 @@
 package components
 
 import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/jakecoffman/cp"
 	ecs "github.com/samix73/ebiten-ecs"
 )
@@ -8,14 +13,32 @@ import (
 func init() {
 	ecs.RegisterComponent[Camera]()
 	ecs.RegisterComponent[ActiveCamera]()
+	ecs.RegisterComponent[CameraFollow]()
 }
 
 var _ ecs.Component = (*Camera)(nil)
 
+// Viewport is the part of a render target a camera draws to, in fractions of the target size
+// measured from its top-left corner. A zero Width or Height covers the whole target.
+type Viewport struct {
+	X, Y          float64
+	Width, Height float64
+}
+
 // Camera represents the viewable area of the game world.
+// The camera is rotated by the Rotation of its Transform.
 type Camera struct {
 	Bounds cp.BB
-	Zoom   float64
+	// Zoom is the number of pixels per world unit, values above 1 zoom in.
+	Zoom float64
+
+	Viewport Viewport
+	// Target is the image the camera draws to, the screen when nil.
+	Target *ebiten.Image `toml:"-"`
+	// Order is the drawing order of cameras; lower values are drawn first.
+	Order int
+	// Background fills the viewport before the camera draws, unless it is transparent.
+	Background color.RGBA
 }
 
 func (c *Camera) Init() {
@@ -25,6 +48,136 @@ func (c *Camera) Init() {
 func (c *Camera) Reset() {
 	c.Bounds = cp.BB{}
 	c.Zoom = 0
+	c.Viewport = Viewport{}
+	c.Target = nil
+	c.Order = 0
+	c.Background = color.RGBA{}
+}
+
+// ZoomFactor returns the scale from world units to pixels, 1 when Zoom is not positive.
+func (c *Camera) ZoomFactor() float64 {
+	if c.Zoom <= 0 {
+		return 1
+	}
+
+	return c.Zoom
+}
+
+// ViewportRect returns the pixels the camera draws to on a target of the given size.
+func (c *Camera) ViewportRect(targetWidth, targetHeight int) image.Rectangle {
+	v := c.Viewport
+	if v.Width <= 0 || v.Height <= 0 {
+		return image.Rect(0, 0, targetWidth, targetHeight)
+	}
+
+	return image.Rect(
+		int(math.Round(v.X*float64(targetWidth))), int(math.Round(v.Y*float64(targetHeight))),
+		int(math.Round((v.X+v.Width)*float64(targetWidth))), int(math.Round((v.Y+v.Height)*float64(targetHeight))),
+	)
+}
+
+// Projection returns the transform from world coordinates to the pixels of a target,
+// for the camera at position rotated by rotation radians drawing to viewport.
+// The camera position is drawn at the center of the viewport and world y points up.
+func (c *Camera) Projection(position cp.Vector, rotation float64, viewport image.Rectangle) ebiten.GeoM {
+	zoom := c.ZoomFactor()
+	center := viewport.Min.Add(viewport.Max)
+
+	var projection ebiten.GeoM
+	projection.Translate(-position.X, -position.Y)
+	projection.Rotate(-rotation)
+	projection.Scale(zoom, -zoom)
+	projection.Translate(float64(center.X)/2, float64(center.Y)/2)
+
+	return projection
+}
+
+// VisibleBounds returns the world area covered by a viewport drawn with projection.
+func VisibleBounds(projection ebiten.GeoM, viewport image.Rectangle) cp.BB {
+	projection.Invert()
+
+	return TransformedBounds(projection, cp.BB{
+		L: float64(viewport.Min.X), B: float64(viewport.Min.Y),
+		R: float64(viewport.Max.X), T: float64(viewport.Max.Y),
+	})
+}
+
+// TransformedBounds returns the axis aligned bounds of the corners of bb transformed by geoM.
+func TransformedBounds(geoM ebiten.GeoM, bb cp.BB) cp.BB {
+	corners := [4]cp.Vector{{X: bb.L, Y: bb.B}, {X: bb.R, Y: bb.B}, {X: bb.R, Y: bb.T}, {X: bb.L, Y: bb.T}}
+
+	bounds := cp.BB{L: math.Inf(1), B: math.Inf(1), R: math.Inf(-1), T: math.Inf(-1)}
+	for _, corner := range corners {
+		x, y := geoM.Apply(corner.X, corner.Y)
+		bounds = bounds.Expand(cp.Vector{X: x, Y: y})
+	}
+
+	return bounds
 }
 
 type ActiveCamera struct{}
+
+// CameraSmoothing decides how a following camera catches up with its target.
+type CameraSmoothing uint8
+
+const (
+	// SmoothingNone moves the camera onto its target immediately.
+	SmoothingNone CameraSmoothing = iota
+	// SmoothingLerp closes a fixed fraction of the remaining distance every second.
+	SmoothingLerp
+	// SmoothingSpring pulls the camera with a damped spring, which eases in and out.
+	SmoothingSpring
+)
+
+var cameraSmoothingNames = newTextEnum("CameraSmoothing", map[CameraSmoothing]string{
+	SmoothingNone:   "none",
+	SmoothingLerp:   "lerp",
+	SmoothingSpring: "spring",
+})
+
+func (s CameraSmoothing) String() string {
+	return cameraSmoothingNames.String(s)
+}
+
+func (s CameraSmoothing) MarshalText() ([]byte, error) {
+	return cameraSmoothingNames.MarshalText(s)
+}
+
+// UnmarshalText decodes a smoothing by its case-insensitive name, so entity TOML can use
+// Smoothing = "spring".
+func (s *CameraSmoothing) UnmarshalText(text []byte) error {
+	return cameraSmoothingNames.UnmarshalText(s, text)
+}
+
+var _ ecs.Component = (*CameraFollow)(nil)
+
+// CameraFollow moves a camera along with a target entity.
+type CameraFollow struct {
+	// Target is the followed entity, the first Player when zero.
+	Target ecs.EntityID
+	// DeadZone is the area around the camera position, in world units, in which the
+	// target moves without moving the camera.
+	DeadZone cp.BB
+
+	Smoothing CameraSmoothing
+	// Speed is the lerp rate per second, or the spring's angular frequency.
+	Speed float64
+	// Damping is the spring's damping ratio, 1 (critically damped) when zero.
+	Damping float64
+
+	// LookAhead leads the target by this many seconds of its RigidBody velocity.
+	LookAhead float64
+	// WorldBounds keeps the camera view inside the world, unbounded when empty.
+	WorldBounds cp.BB
+
+	// Velocity is the current velocity of a spring smoothed camera.
+	Velocity cp.Vector
+}
+
+func (c *CameraFollow) Init() {
+	*c = CameraFollow{}
+}
+
+func (c *CameraFollow) Reset() {
+	*c = CameraFollow{}
+}
@@ -8,16 +8,62 @@ import (
 func init() {
 	ecs.RegisterComponent[Collider]()
 	ecs.RegisterComponent[Collision]()
+	ecs.RegisterComponent[CollisionEvents]()
 }
 
 var _ ecs.Component = (*Collider)(nil)
 
 type Collider struct {
 	Bounds cp.BB
+	// Slice takes the bounds from a slice of an Aseprite sheet when Bounds is empty,
+	// such as Slice = "biog-flap.json#body", once SpriteSliceSystem loaded it.
+	Slice SpriteSlice
+	// Trigger colliders report contacts and events but are never physically resolved.
+	Trigger bool
+	// Category is the set of layers the collider belongs to, LayerDefault when zero.
+	Category CollisionLayer
+	// Mask is the set of layers the collider collides with, LayerAll when zero.
+	Mask CollisionLayer
 }
 
 func (c *Collider) Init() {
 	c.Bounds = cp.BB{}
+	c.Slice = SpriteSlice{}
+	c.Trigger = false
+	c.Category = LayerDefault
+	c.Mask = LayerAll
+}
+
+// LocalBounds returns the bounds of the collider around its entity's position,
+// the bounds of its loaded Slice when Bounds is empty.
+func (c *Collider) LocalBounds() cp.BB {
+	if c.Bounds == (cp.BB{}) && c.Slice.Loaded() {
+		return c.Slice.Bounds
+	}
+
+	return c.Bounds
+}
+
+// Layers returns the category and mask of the collider. Zero values fall back to the
+// defaults, so colliders without layers keep colliding with everything.
+func (c *Collider) Layers() (category, mask CollisionLayer) {
+	category, mask = c.Category, c.Mask
+	if category == LayerNone {
+		category = LayerDefault
+	}
+	if mask == LayerNone {
+		mask = LayerAll
+	}
+
+	return category, mask
+}
+
+// CanCollide reports whether both colliders accept each other's category.
+func (c *Collider) CanCollide(other *Collider) bool {
+	category, mask := c.Layers()
+	otherCategory, otherMask := other.Layers()
+
+	return category&otherMask != 0 && otherCategory&mask != 0
 }
 
 // SetSize sets the size of the collider bounds, centered at (0,0).
@@ -29,20 +75,68 @@ func (c *Collider) SetSize(width, height float64) {
 
 func (c *Collider) Reset() {
 	c.Bounds = cp.BB{}
+	c.Slice = SpriteSlice{}
+	c.Trigger = false
+	c.Category = LayerDefault
+	c.Mask = LayerAll
+}
+
+// Contact is an overlap between the entity owning it and another collider.
+type Contact struct {
+	Entity      ecs.EntityID
+	Penetration float64
+	// Normal points from the owning entity towards Entity.
+	Normal cp.Vector
+	// Trigger is set when either collider is a trigger.
+	Trigger bool
 }
 
 var _ ecs.Component = (*Collision)(nil)
 
+// Collision lists every collider an entity currently overlaps.
 type Collision struct {
-	Entity      ecs.EntityID
-	Penetration float64
-	Normal      cp.Vector
+	Contacts []Contact
 }
 
-func (c *Collision) Init() {}
+// With returns the contact with the given entity, if any.
+func (c *Collision) With(entity ecs.EntityID) (Contact, bool) {
+	for _, contact := range c.Contacts {
+		if contact.Entity == entity {
+			return contact, true
+		}
+	}
+
+	return Contact{}, false
+}
+
+func (c *Collision) Init() {
+	c.Contacts = c.Contacts[:0]
+}
 
 func (c *Collision) Reset() {
-	c.Entity = 0
-	c.Penetration = 0
-	c.Normal = cp.Vector{}
+	c.Contacts = c.Contacts[:0]
+}
+
+var _ ecs.Component = (*CollisionEvents)(nil)
+
+// CollisionEvents lists the contacts that started, continued and ended this frame, over
+// every physics step of the frame. It is only present on entities that have at least one event.
+type CollisionEvents struct {
+	Enter []Contact
+	Stay  []Contact
+	// Exit holds the last known state of contacts that ended,
+	// their entity may have been removed already.
+	Exit []Contact
+}
+
+func (c *CollisionEvents) Init() {
+	c.Enter = c.Enter[:0]
+	c.Stay = c.Stay[:0]
+	c.Exit = c.Exit[:0]
+}
+
+func (c *CollisionEvents) Reset() {
+	c.Enter = c.Enter[:0]
+	c.Stay = c.Stay[:0]
+	c.Exit = c.Exit[:0]
 }
@@ -0,0 +1,93 @@
+package components
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// CollisionLayer is a bitmask of collision categories.
+// In entity TOML it is written as a layer name or a list of layer names,
+// for example Category = "player" and Mask = ["default", "obstacle"].
+// An empty list leaves the layer zero, which Collider treats as its default.
+type CollisionLayer uint32
+
+const (
+	LayerDefault CollisionLayer = 1 << iota
+	LayerPlayer
+	LayerObstacle
+	LayerWall
+	LayerPickup
+	LayerDecoration
+
+	LayerNone CollisionLayer = 0
+	LayerAll  CollisionLayer = ^LayerNone
+)
+
+var collisionLayers = map[string]CollisionLayer{
+	"all":        LayerAll,
+	"default":    LayerDefault,
+	"player":     LayerPlayer,
+	"obstacle":   LayerObstacle,
+	"wall":       LayerWall,
+	"pickup":     LayerPickup,
+	"decoration": LayerDecoration,
+}
+
+// CollisionLayerByName returns the layer with the given case-insensitive name.
+func CollisionLayerByName(name string) (CollisionLayer, bool) {
+	layer, ok := collisionLayers[strings.ToLower(name)]
+	return layer, ok
+}
+
+// UnmarshalTOML decodes a layer name or a list of layer names.
+func (l *CollisionLayer) UnmarshalTOML(data any) error {
+	var names []any
+	switch v := data.(type) {
+	case string:
+		names = []any{v}
+	case []any:
+		names = v
+	default:
+		return fmt.Errorf("components.CollisionLayer.UnmarshalTOML: expected a layer name or a list of names, got %T", data)
+	}
+
+	layer := LayerNone
+	for _, n := range names {
+		name, ok := n.(string)
+		if !ok {
+			return fmt.Errorf("components.CollisionLayer.UnmarshalTOML: expected a layer name, got %T", n)
+		}
+
+		named, ok := CollisionLayerByName(name)
+		if !ok {
+			return fmt.Errorf("components.CollisionLayer.UnmarshalTOML: unknown layer %q", name)
+		}
+
+		layer |= named
+	}
+
+	*l = layer
+
+	return nil
+}
+
+func (l CollisionLayer) String() string {
+	switch l {
+	case LayerNone:
+		return "none"
+	case LayerAll:
+		return "all"
+	}
+
+	names := make([]string, 0, bits.OnesCount32(uint32(l)))
+	for name, layer := range collisionLayers {
+		if bits.OnesCount32(uint32(layer)) == 1 && l&layer != 0 {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	return strings.Join(names, "|")
+}
@@ -0,0 +1,47 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollisionLayer_UnmarshalTOML(t *testing.T) {
+	var collider Collider
+	collider.Init()
+
+	_, err := toml.Decode(`
+Category = "player"
+Mask = ["Default", "obstacle"]
+`, &collider)
+	require.NoError(t, err)
+	require.Equal(t, LayerPlayer, collider.Category)
+	require.Equal(t, LayerDefault|LayerObstacle, collider.Mask)
+	require.Equal(t, "default|obstacle", collider.Mask.String())
+
+	_, err = toml.Decode(`Mask = ["ghost"]`, &collider)
+	require.ErrorContains(t, err, "unknown layer")
+}
+
+func TestCollider_CanCollide(t *testing.T) {
+	var player, pipe, decoration Collider
+	for _, c := range []*Collider{&player, &pipe, &decoration} {
+		c.Init()
+	}
+
+	player.Category = LayerPlayer
+	player.Mask = LayerAll &^ LayerDecoration
+	pipe.Category = LayerObstacle
+	decoration.Category = LayerDecoration
+
+	// Colliders decoded from TOML skip Init and still collide with everything.
+	var zero Collider
+	require.True(t, zero.CanCollide(&pipe))
+	require.True(t, zero.CanCollide(&decoration))
+
+	require.True(t, player.CanCollide(&pipe))
+	require.True(t, pipe.CanCollide(&player))
+	require.False(t, player.CanCollide(&decoration))
+	require.False(t, decoration.CanCollide(&player))
+}
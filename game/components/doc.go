@@ -0,0 +1,6 @@
+// Package components holds the ECS components of the game.
+//
+// Components loaded from entity TOML are decoded into their zero value, Init is not
+// called. Fields whose zero value is not a usable default, such as Camera.Zoom or
+// Collider.Category, are read through methods that substitute the default for zero.
+package components
@@ -0,0 +1,41 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+)
+
+// textEnum holds the names of the values of an enum, so the enum can be written by name in
+// entity TOML. Enums implement String, MarshalText and UnmarshalText by calling it.
+type textEnum[T ~uint8] struct {
+	typeName string
+	names    map[T]string
+}
+
+func newTextEnum[T ~uint8](typeName string, names map[T]string) textEnum[T] {
+	return textEnum[T]{typeName: typeName, names: names}
+}
+
+func (e textEnum[T]) String(v T) string {
+	if name, ok := e.names[v]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("%s(%d)", e.typeName, v)
+}
+
+func (e textEnum[T]) MarshalText(v T) ([]byte, error) {
+	return []byte(e.String(v)), nil
+}
+
+// UnmarshalText decodes a value by its case-insensitive name.
+func (e textEnum[T]) UnmarshalText(v *T, text []byte) error {
+	for value, name := range e.names {
+		if strings.EqualFold(name, string(text)) {
+			*v = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("components.%s.UnmarshalText: unknown name %q", e.typeName, text)
+}
@@ -0,0 +1,21 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextEnum(t *testing.T) {
+	text, err := BodyKinematic.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "kinematic", string(text))
+	require.Equal(t, "BodyType(9)", BodyType(9).String())
+
+	var bodyType BodyType
+	require.NoError(t, bodyType.UnmarshalText([]byte("Static")))
+	require.Equal(t, BodyStatic, bodyType)
+
+	var smoothing CameraSmoothing
+	require.EqualError(t, smoothing.UnmarshalText([]byte("ease")), `components.CameraSmoothing.UnmarshalText: unknown name "ease"`)
+}
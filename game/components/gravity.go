@@ -0,0 +1,50 @@
+package components
+
+import (
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+func init() {
+	ecs.RegisterComponent[WorldGravity]()
+	ecs.RegisterComponent[GravityZone]()
+}
+
+var _ ecs.Component = (*WorldGravity)(nil)
+
+// WorldGravity sets the gravity of a world. Worlds add it through a settings entity,
+// the physics systems fall back to their default gravity without one.
+type WorldGravity struct {
+	Acceleration cp.Vector
+}
+
+func (w *WorldGravity) Init() {
+	w.Acceleration = cp.Vector{}
+}
+
+func (w *WorldGravity) Reset() {
+	w.Acceleration = cp.Vector{}
+}
+
+var _ ecs.Component = (*GravityZone)(nil)
+
+// GravityZone overrides the world gravity for bodies whose position is inside Bounds.
+// Bounds are relative to the entity Transform, if it has one.
+// When zones overlap the one with the highest Priority wins.
+type GravityZone struct {
+	Bounds       cp.BB
+	Acceleration cp.Vector
+	Priority     int
+}
+
+func (g *GravityZone) Init() {
+	g.Bounds = cp.BB{}
+	g.Acceleration = cp.Vector{}
+	g.Priority = 0
+}
+
+func (g *GravityZone) Reset() {
+	g.Bounds = cp.BB{}
+	g.Acceleration = cp.Vector{}
+	g.Priority = 0
+}
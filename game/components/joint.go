@@ -0,0 +1,100 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+func init() {
+	ecs.RegisterComponent[Joint]()
+}
+
+// JointType decides how a Joint constrains the two entities it links.
+type JointType uint8
+
+const (
+	// JointPin keeps the anchors at the distance they had when the joint was created.
+	JointPin JointType = iota
+	// JointSlide keeps the distance between the anchors between Min and Max.
+	JointSlide
+	// JointPivot holds the anchors together, letting the bodies rotate around them.
+	JointPivot
+	// JointSpring pulls the anchors towards RestLength with a damped spring.
+	JointSpring
+	// JointRope keeps the anchors at most Max apart, letting them move freely when closer.
+	JointRope
+)
+
+var jointTypeNames = newTextEnum("JointType", map[JointType]string{
+	JointPin:    "pin",
+	JointSlide:  "slide",
+	JointPivot:  "pivot",
+	JointSpring: "spring",
+	JointRope:   "rope",
+})
+
+func (j JointType) String() string {
+	return jointTypeNames.String(j)
+}
+
+func (j JointType) MarshalText() ([]byte, error) {
+	return jointTypeNames.MarshalText(j)
+}
+
+// UnmarshalText decodes a joint type by its case-insensitive name, so entity TOML can use
+// Type = "rope".
+func (j *JointType) UnmarshalText(text []byte) error {
+	return jointTypeNames.UnmarshalText(j, text)
+}
+
+var _ ecs.Component = (*Joint)(nil)
+
+// Joint links the bodies of two entities with a constraint. It lives on its own entity,
+// which is removed once either linked entity is removed or loses its Transform.
+// Linked entities need a Collider and a Transform to be simulated. In world TOML they are
+// referenced by ID, entities get IDs in the order the world lists them, starting at 1.
+type Joint struct {
+	Type JointType
+
+	EntityA ecs.EntityID
+	EntityB ecs.EntityID
+	// AnchorA and AnchorB are the attachment points, relative to the position of each entity.
+	AnchorA cp.Vector
+	AnchorB cp.Vector
+
+	// Min and Max bound the distance between the anchors of slide joints,
+	// rope joints only use Max.
+	Min float64
+	Max float64
+
+	// RestLength, Stiffness and Damping configure spring joints.
+	RestLength float64
+	Stiffness  float64
+	Damping    float64
+
+	// Collide lets the two linked entities keep colliding with each other.
+	Collide bool
+}
+
+// Validate reports joints that don't link two different entities.
+func (j *Joint) Validate() error {
+	switch {
+	case j.EntityA == 0 || j.EntityB == 0:
+		return errors.New("components.Joint.Validate: EntityA and EntityB are required")
+	case j.EntityA == j.EntityB:
+		return fmt.Errorf("components.Joint.Validate: entity %d is linked to itself", j.EntityA)
+	}
+
+	return nil
+}
+
+func (j *Joint) Init() {
+	*j = Joint{}
+}
+
+func (j *Joint) Reset() {
+	*j = Joint{}
+}
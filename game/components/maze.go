@@ -2,6 +2,7 @@ package components
 
 import (
 	"errors"
+	"fmt"
 	"math/rand/v2"
 
 	"github.com/jakecoffman/cp"
@@ -12,12 +13,20 @@ func init() {
 	ecs.RegisterComponent[Maze]()
 }
 
+const (
+	MazeWall  uint8 = 0
+	MazeFloor uint8 = 1
+)
+
+// Maze is a grid of wall and floor cells, indexed as Cells[y][x].
 type Maze struct {
 	Width    int
 	Height   int
 	Cells    [][]uint8
 	Seed     uint64
 	CellSize float64
+	Entry    cp.Vector
+	Exit     cp.Vector
 }
 
 func (m *Maze) Init() {
@@ -25,6 +34,8 @@ func (m *Maze) Init() {
 	m.Height = 0
 	m.Cells = nil
 	m.CellSize = 0
+	m.Entry = cp.Vector{}
+	m.Exit = cp.Vector{}
 }
 
 func (m *Maze) Reset() {
@@ -32,6 +43,8 @@ func (m *Maze) Reset() {
 	m.Height = 0
 	m.Cells = nil
 	m.CellSize = 0
+	m.Entry = cp.Vector{}
+	m.Exit = cp.Vector{}
 }
 
 func (m *Maze) Clone() ecs.Component {
@@ -39,7 +52,27 @@ func (m *Maze) Clone() ecs.Component {
 	return &clone
 }
 
-func GenerateMaze(width, height int, entry, exit cp.Vector, seed uint64, cellSize float64) (Maze, error) {
+// IsWall reports whether the cell at (x, y) is a wall.
+// Cells outside the maze are treated as walls.
+func (m *Maze) IsWall(x, y int) bool {
+	if y < 0 || y >= len(m.Cells) || x < 0 || x >= len(m.Cells[y]) {
+		return true
+	}
+
+	return m.Cells[y][x] == MazeWall
+}
+
+// GenerateMaze generates a maze of the given size that connects entry and exit.
+// Passages are carved between "rooms" at odd coordinates using opts.Algorithm,
+// so the result is a perfect maze (exactly one path between any two rooms) unless
+// opts.Braid removes dead ends. The cells on the edge of the maze are walls except
+// where entry or exit lie on them. Odd sizes fit the rooms exactly, even sizes leave
+// a thicker wall on the right or bottom. The result is deterministic for a given seed and options.
+func GenerateMaze(width, height int, entry, exit cp.Vector, seed uint64, cellSize float64, opts MazeOptions) (Maze, error) {
+	if width < 3 || height < 3 {
+		return Maze{}, errors.New("width and height must be at least 3")
+	}
+
 	if entry == exit {
 		return Maze{}, errors.New("entry and exit must be different")
 	}
@@ -52,91 +85,33 @@ func GenerateMaze(width, height int, entry, exit cp.Vector, seed uint64, cellSiz
 		return Maze{}, errors.New("exit point is out of bounds")
 	}
 
-	// Initialize all cells as walls (false)
-	cells := make([][]uint8, height)
-	for i := range cells {
-		cells[i] = make([]uint8, width)
+	if opts.Braid < 0 || opts.Braid > 1 {
+		return Maze{}, errors.New("braid must be between 0 and 1")
 	}
 
-	r := rand.NewPCG(seed, 0)
-
-	// Create a guaranteed path from entry to exit using random walk
-	entryX, entryY := int(entry.X), int(entry.Y)
-	exitX, exitY := int(exit.X), int(exit.Y)
-
-	// Mark entry and exit as open
-	cells[entryY][entryX] = 1
-	cells[exitY][exitX] = 1
-
-	// Random walk from entry to exit to ensure connectivity
-	currentX, currentY := entryX, entryY
-
-	for currentX != exitX || currentY != exitY {
-		// Mark current cell as open
-		cells[currentY][currentX] = 1
-
-		// Calculate direction bias towards exit
-		directions := make([][2]int, 0, 4)
-
-		// Prefer moving towards exit
-		if currentX < exitX && currentX+1 < width {
-			directions = append(directions, [2]int{1, 0}) // right
-		}
-		if currentX > exitX && currentX-1 >= 0 {
-			directions = append(directions, [2]int{-1, 0}) // left
-		}
-		if currentY < exitY && currentY+1 < height {
-			directions = append(directions, [2]int{0, 1}) // down
-		}
-		if currentY > exitY && currentY-1 >= 0 {
-			directions = append(directions, [2]int{0, -1}) // up
-		}
-
-		// Add other valid directions
-		allDirections := [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
-		for _, dir := range allDirections {
-			newX, newY := currentX+dir[0], currentY+dir[1]
-			if newX >= 0 && newX < width && newY >= 0 && newY < height {
-				// Add non-biased directions with lower priority
-				alreadyAdded := false
-				for _, d := range directions {
-					if d[0] == dir[0] && d[1] == dir[1] {
-						alreadyAdded = true
-						break
-					}
-				}
-				if !alreadyAdded {
-					directions = append(directions, dir)
-				}
-			}
-		}
-
-		if len(directions) == 0 {
-			// Should not happen with valid entry/exit, but handle gracefully
-			break
-		}
-
-		// Choose a direction (biased towards exit due to ordering)
-		chosenDir := directions[r.Uint64()%uint64(len(directions))]
-		currentX += chosenDir[0]
-		currentY += chosenDir[1]
+	carve, ok := mazeAlgorithms[opts.Algorithm]
+	if !ok {
+		return Maze{}, fmt.Errorf("unknown maze algorithm %q", opts.Algorithm)
 	}
 
-	// Fill remaining cells randomly, keeping the guaranteed path
-	for y := range cells {
-		for x := range cells[y] {
-			// Skip cells that are already marked as open (part of the path)
-			if cells[y][x] == 0 {
-				cells[y][x] = uint8(r.Uint64() % 2)
-			}
-		}
+	g := newMazeGrid(width, height, rand.New(rand.NewPCG(seed, 0)))
+
+	carve(g)
+
+	if opts.Braid > 0 {
+		g.braid(opts.Braid)
 	}
 
+	g.connect(int(entry.X), int(entry.Y))
+	g.connect(int(exit.X), int(exit.Y))
+
 	return Maze{
 		Width:    width,
 		Height:   height,
-		Cells:    cells,
+		Cells:    g.cells,
 		Seed:     seed,
 		CellSize: cellSize,
+		Entry:    entry,
+		Exit:     exit,
 	}, nil
 }
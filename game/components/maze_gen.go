@@ -0,0 +1,323 @@
+package components
+
+import (
+	"math/rand/v2"
+)
+
+// MazeAlgorithm names an algorithm used by GenerateMaze to carve passages.
+type MazeAlgorithm string
+
+const (
+	MazeBacktracker MazeAlgorithm = "backtracker"
+	MazePrim        MazeAlgorithm = "prim"
+	MazeKruskal     MazeAlgorithm = "kruskal"
+	MazeWilson      MazeAlgorithm = "wilson"
+)
+
+// MazeOptions configures GenerateMaze.
+type MazeOptions struct {
+	// Algorithm selects how passages are carved. Defaults to MazeBacktracker.
+	Algorithm MazeAlgorithm
+	// Braid is the probability, between 0 and 1, that a dead end is opened into a loop.
+	Braid float64
+}
+
+var mazeAlgorithms = map[MazeAlgorithm]func(g *mazeGrid){
+	"":              (*mazeGrid).backtracker,
+	MazeBacktracker: (*mazeGrid).backtracker,
+	MazePrim:        (*mazeGrid).prim,
+	MazeKruskal:     (*mazeGrid).kruskal,
+	MazeWilson:      (*mazeGrid).wilson,
+}
+
+// MazeAlgorithms returns the algorithms supported by GenerateMaze.
+func MazeAlgorithms() []MazeAlgorithm {
+	return []MazeAlgorithm{MazeBacktracker, MazePrim, MazeKruskal, MazeWilson}
+}
+
+// mazeRoom is a room in room coordinates; room (x, y) is the cell (2x+1, 2y+1).
+type mazeRoom struct {
+	x, y int
+}
+
+type mazeEdge struct {
+	a, b mazeRoom
+}
+
+// mazeGrid carves passages between rooms laid out on the odd cells of a maze.
+// The even cells between two rooms are the walls that get opened, and the cells
+// on the edge of the maze stay walls so the maze is closed.
+type mazeGrid struct {
+	cells [][]uint8
+	cols  int
+	rows  int
+	r     *rand.Rand
+}
+
+func newMazeGrid(width, height int, r *rand.Rand) *mazeGrid {
+	g := &mazeGrid{
+		cells: make([][]uint8, height),
+		cols:  (width - 1) / 2,
+		rows:  (height - 1) / 2,
+		r:     r,
+	}
+
+	for y := range g.cells {
+		g.cells[y] = make([]uint8, width)
+	}
+
+	for ry := range g.rows {
+		for rx := range g.cols {
+			g.cells[ry*2+1][rx*2+1] = MazeFloor
+		}
+	}
+
+	return g
+}
+
+func (g *mazeGrid) index(room mazeRoom) int {
+	return room.y*g.cols + room.x
+}
+
+func (g *mazeGrid) room(index int) mazeRoom {
+	return mazeRoom{x: index % g.cols, y: index / g.cols}
+}
+
+func (g *mazeGrid) randomRoom() mazeRoom {
+	return g.room(g.r.IntN(g.cols * g.rows))
+}
+
+func (g *mazeGrid) neighbors(room mazeRoom) []mazeRoom {
+	neighbors := make([]mazeRoom, 0, 4)
+
+	for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		n := mazeRoom{x: room.x + d[0], y: room.y + d[1]}
+		if n.x < 0 || n.y < 0 || n.x >= g.cols || n.y >= g.rows {
+			continue
+		}
+
+		neighbors = append(neighbors, n)
+	}
+
+	return neighbors
+}
+
+// carve opens the wall between two adjacent rooms.
+func (g *mazeGrid) carve(a, b mazeRoom) {
+	g.cells[a.y+b.y+1][a.x+b.x+1] = MazeFloor
+}
+
+func (g *mazeGrid) connected(a, b mazeRoom) bool {
+	return g.cells[a.y+b.y+1][a.x+b.x+1] == MazeFloor
+}
+
+func (g *mazeGrid) passages(room mazeRoom) int {
+	count := 0
+	for _, n := range g.neighbors(room) {
+		if g.connected(room, n) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// backtracker carves a maze with a randomized depth-first search.
+func (g *mazeGrid) backtracker() {
+	visited := make([]bool, g.cols*g.rows)
+
+	start := g.randomRoom()
+	visited[g.index(start)] = true
+	stack := []mazeRoom{start}
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+
+		candidates := make([]mazeRoom, 0, 4)
+		for _, n := range g.neighbors(current) {
+			if !visited[g.index(n)] {
+				candidates = append(candidates, n)
+			}
+		}
+
+		if len(candidates) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		next := candidates[g.r.IntN(len(candidates))]
+		g.carve(current, next)
+		visited[g.index(next)] = true
+		stack = append(stack, next)
+	}
+}
+
+// prim carves a maze with randomized Prim's algorithm, growing from a random room.
+func (g *mazeGrid) prim() {
+	inMaze := make([]bool, g.cols*g.rows)
+	frontier := make([]mazeEdge, 0)
+
+	add := func(room mazeRoom) {
+		inMaze[g.index(room)] = true
+		for _, n := range g.neighbors(room) {
+			if !inMaze[g.index(n)] {
+				frontier = append(frontier, mazeEdge{a: room, b: n})
+			}
+		}
+	}
+
+	add(g.randomRoom())
+
+	for len(frontier) > 0 {
+		i := g.r.IntN(len(frontier))
+		edge := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+
+		if inMaze[g.index(edge.b)] {
+			continue
+		}
+
+		g.carve(edge.a, edge.b)
+		add(edge.b)
+	}
+}
+
+// kruskal carves a maze with randomized Kruskal's algorithm over all walls.
+func (g *mazeGrid) kruskal() {
+	parent := make([]int, g.cols*g.rows)
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+
+		return parent[i]
+	}
+
+	edges := make([]mazeEdge, 0, 2*g.cols*g.rows)
+	for y := range g.rows {
+		for x := range g.cols {
+			room := mazeRoom{x: x, y: y}
+			if x+1 < g.cols {
+				edges = append(edges, mazeEdge{a: room, b: mazeRoom{x: x + 1, y: y}})
+			}
+			if y+1 < g.rows {
+				edges = append(edges, mazeEdge{a: room, b: mazeRoom{x: x, y: y + 1}})
+			}
+		}
+	}
+
+	g.r.Shuffle(len(edges), func(i, j int) {
+		edges[i], edges[j] = edges[j], edges[i]
+	})
+
+	for _, edge := range edges {
+		a, b := find(g.index(edge.a)), find(g.index(edge.b))
+		if a == b {
+			continue
+		}
+
+		parent[a] = b
+		g.carve(edge.a, edge.b)
+	}
+}
+
+// wilson carves a uniform spanning tree with Wilson's loop-erased random walks.
+func (g *mazeGrid) wilson() {
+	inMaze := make([]bool, g.cols*g.rows)
+	inMaze[g.index(g.randomRoom())] = true
+
+	// next records the last direction taken out of each room, which erases loops implicitly.
+	next := make([]mazeRoom, g.cols*g.rows)
+
+	for i := range inMaze {
+		if inMaze[i] {
+			continue
+		}
+
+		current := g.room(i)
+		for !inMaze[g.index(current)] {
+			neighbors := g.neighbors(current)
+			n := neighbors[g.r.IntN(len(neighbors))]
+			next[g.index(current)] = n
+			current = n
+		}
+
+		current = g.room(i)
+		for !inMaze[g.index(current)] {
+			n := next[g.index(current)]
+			g.carve(current, n)
+			inMaze[g.index(current)] = true
+			current = n
+		}
+	}
+}
+
+// braid opens each dead end into a loop with the given probability,
+// preferring to join it to a neighbouring dead end.
+func (g *mazeGrid) braid(probability float64) {
+	for y := range g.rows {
+		for x := range g.cols {
+			room := mazeRoom{x: x, y: y}
+			if g.passages(room) != 1 {
+				continue
+			}
+
+			if g.r.Float64() >= probability {
+				continue
+			}
+
+			closed := make([]mazeRoom, 0, 3)
+			deadEnds := make([]mazeRoom, 0, 3)
+			for _, n := range g.neighbors(room) {
+				if g.connected(room, n) {
+					continue
+				}
+
+				closed = append(closed, n)
+				if g.passages(n) == 1 {
+					deadEnds = append(deadEnds, n)
+				}
+			}
+
+			if len(deadEnds) > 0 {
+				closed = deadEnds
+			}
+
+			if len(closed) == 0 {
+				continue
+			}
+
+			g.carve(room, closed[g.r.IntN(len(closed))])
+		}
+	}
+}
+
+// nearestRoomCell returns the odd coordinate of the room closest to v, for rooms up to count.
+func nearestRoomCell(v, count int) int {
+	if v%2 == 0 {
+		v++
+	}
+
+	return min(max(v, 1), 2*count-1)
+}
+
+// connect opens the cell at (x, y) and joins it to the nearest room, first along the row
+// and then along the column of the room. Cells on the edge of the maze become openings
+// in its outer wall.
+func (g *mazeGrid) connect(x, y int) {
+	rx, ry := nearestRoomCell(x, g.cols), nearestRoomCell(y, g.rows)
+
+	for cx := min(x, rx); cx <= max(x, rx); cx++ {
+		g.cells[y][cx] = MazeFloor
+	}
+
+	for cy := min(y, ry); cy <= max(y, ry); cy++ {
+		g.cells[cy][rx] = MazeFloor
+	}
+}
@@ -0,0 +1,167 @@
+package components
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	"github.com/stretchr/testify/require"
+)
+
+// deadEnds counts the rooms with a single passage.
+func deadEnds(g *mazeGrid) int {
+	count := 0
+	for y := range g.rows {
+		for x := range g.cols {
+			if g.passages(mazeRoom{x: x, y: y}) == 1 {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+// reachableRooms counts the rooms reachable from room (0, 0) through carved passages.
+func reachableRooms(g *mazeGrid) int {
+	visited := map[mazeRoom]bool{{}: true}
+	queue := []mazeRoom{{}}
+
+	for len(queue) > 0 {
+		room := queue[0]
+		queue = queue[1:]
+
+		for _, n := range g.neighbors(room) {
+			if g.connected(room, n) && !visited[n] {
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	return len(visited)
+}
+
+// passageCount counts the carved walls between rooms.
+func passageCount(g *mazeGrid) int {
+	count := 0
+	for y := range g.rows {
+		for x := range g.cols {
+			count += g.passages(mazeRoom{x: x, y: y})
+		}
+	}
+
+	return count / 2
+}
+
+func TestMazeAlgorithms_Perfect(t *testing.T) {
+	for _, algorithm := range MazeAlgorithms() {
+		t.Run(string(algorithm), func(t *testing.T) {
+			for seed := range uint64(5) {
+				g := newMazeGrid(21, 15, rand.New(rand.NewPCG(seed, 0)))
+				mazeAlgorithms[algorithm](g)
+
+				// A spanning tree connects every room with one passage less than there are rooms,
+				// so there is exactly one path between any two rooms.
+				rooms := g.cols * g.rows
+				require.Equal(t, rooms, reachableRooms(g))
+				require.Equal(t, rooms-1, passageCount(g))
+			}
+		})
+	}
+}
+
+func TestMazeGrid_Braid(t *testing.T) {
+	for _, algorithm := range MazeAlgorithms() {
+		t.Run(string(algorithm), func(t *testing.T) {
+			perfect := newMazeGrid(21, 15, rand.New(rand.NewPCG(3, 0)))
+			mazeAlgorithms[algorithm](perfect)
+
+			half := newMazeGrid(21, 15, rand.New(rand.NewPCG(3, 0)))
+			mazeAlgorithms[algorithm](half)
+			half.braid(0.5)
+
+			full := newMazeGrid(21, 15, rand.New(rand.NewPCG(3, 0)))
+			mazeAlgorithms[algorithm](full)
+			full.braid(1)
+
+			require.Positive(t, deadEnds(perfect))
+			require.Less(t, deadEnds(half), deadEnds(perfect))
+			require.Zero(t, deadEnds(full))
+
+			// Braiding only opens walls, every room stays reachable.
+			require.Equal(t, full.cols*full.rows, reachableRooms(full))
+		})
+	}
+}
+
+func TestGenerateMaze(t *testing.T) {
+	entry := cp.Vector{X: 1, Y: 0}
+	exit := cp.Vector{X: 19, Y: 14}
+
+	for _, algorithm := range MazeAlgorithms() {
+		t.Run(string(algorithm), func(t *testing.T) {
+			m, err := GenerateMaze(21, 15, entry, exit, 42, 32, MazeOptions{Algorithm: algorithm})
+			require.NoError(t, err)
+			require.Len(t, m.Cells, 15)
+			require.Len(t, m.Cells[0], 21)
+
+			again, err := GenerateMaze(21, 15, entry, exit, 42, 32, MazeOptions{Algorithm: algorithm})
+			require.NoError(t, err)
+			require.Equal(t, m.Cells, again.Cells)
+
+			other, err := GenerateMaze(21, 15, entry, exit, 43, 32, MazeOptions{Algorithm: algorithm})
+			require.NoError(t, err)
+			require.NotEqual(t, m.Cells, other.Cells)
+
+			// The outer wall is closed except for the entry and exit.
+			for y := range m.Height {
+				for x := range m.Width {
+					if x != 0 && y != 0 && x != m.Width-1 && y != m.Height-1 {
+						continue
+					}
+
+					open := (x == 1 && y == 0) || (x == 19 && y == 14)
+					require.Equal(t, open, !m.IsWall(x, y), "edge cell %d,%d", x, y)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateMaze_EvenSize(t *testing.T) {
+	m, err := GenerateMaze(10, 8, cp.Vector{X: 0, Y: 1}, cp.Vector{X: 9, Y: 6}, 1, 1, MazeOptions{})
+	require.NoError(t, err)
+
+	// The extra column and row are walls, the openings reach through them to the nearest room.
+	require.False(t, m.IsWall(0, 1))
+	require.False(t, m.IsWall(9, 6))
+	require.False(t, m.IsWall(8, 6))
+	require.False(t, m.IsWall(7, 6))
+	for x := range m.Width {
+		require.True(t, m.IsWall(x, 7))
+	}
+}
+
+func TestGenerateMaze_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		entry, exit   cp.Vector
+		opts          MazeOptions
+	}{
+		{name: "too small", width: 2, height: 5, exit: cp.Vector{X: 1}},
+		{name: "same entry and exit", width: 5, height: 5},
+		{name: "entry out of bounds", width: 5, height: 5, entry: cp.Vector{X: 5}},
+		{name: "exit out of bounds", width: 5, height: 5, exit: cp.Vector{Y: -1}},
+		{name: "braid", width: 5, height: 5, exit: cp.Vector{X: 4}, opts: MazeOptions{Braid: 2}},
+		{name: "algorithm", width: 5, height: 5, exit: cp.Vector{X: 4}, opts: MazeOptions{Algorithm: "eller"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateMaze(tt.width, tt.height, tt.entry, tt.exit, 1, 1, tt.opts)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package components
+
+import ecs "github.com/samix73/ebiten-ecs"
+
+func init() {
+	ecs.RegisterComponent[PhysicsMaterial]()
+}
+
+// CombineMode decides how the values of two materials in contact are combined.
+// When the two materials use different modes the one declared last wins,
+// so Maximum takes precedence over Multiply, Minimum and Average.
+type CombineMode uint8
+
+const (
+	CombineAverage CombineMode = iota
+	CombineMinimum
+	CombineMultiply
+	CombineMaximum
+)
+
+var combineModeNames = newTextEnum("CombineMode", map[CombineMode]string{
+	CombineAverage:  "average",
+	CombineMinimum:  "minimum",
+	CombineMultiply: "multiply",
+	CombineMaximum:  "maximum",
+})
+
+// Combine combines a and b with the mode that takes precedence between m and other.
+func (m CombineMode) Combine(other CombineMode, a, b float64) float64 {
+	switch max(m, other) {
+	case CombineMinimum:
+		return min(a, b)
+	case CombineMultiply:
+		return a * b
+	case CombineMaximum:
+		return max(a, b)
+	default:
+		return (a + b) / 2
+	}
+}
+
+func (m CombineMode) String() string {
+	return combineModeNames.String(m)
+}
+
+func (m CombineMode) MarshalText() ([]byte, error) {
+	return combineModeNames.MarshalText(m)
+}
+
+// UnmarshalText decodes a mode by its case-insensitive name, so entity TOML can use
+// FrictionCombine = "maximum".
+func (m *CombineMode) UnmarshalText(text []byte) error {
+	return combineModeNames.UnmarshalText(m, text)
+}
+
+var _ ecs.Component = (*PhysicsMaterial)(nil)
+
+// PhysicsMaterial describes how a collider bounces and slides against others.
+// Dynamic bodies without a material use DefaultPhysicsMaterial, all other colliders
+// without one use DefaultStaticPhysicsMaterial.
+type PhysicsMaterial struct {
+	// Restitution is the bounciness, 0 stops along the contact normal and 1 bounces back fully.
+	Restitution float64
+	// StaticFriction is the friction coefficient that holds a resting contact in place.
+	StaticFriction float64
+	// DynamicFriction is the friction coefficient applied to a sliding contact.
+	DynamicFriction float64
+
+	RestitutionCombine CombineMode
+	FrictionCombine    CombineMode
+}
+
+// DefaultPhysicsMaterial is a bouncy frictionless material.
+func DefaultPhysicsMaterial() PhysicsMaterial {
+	return PhysicsMaterial{
+		Restitution: 0.8,
+	}
+}
+
+// DefaultStaticPhysicsMaterial is a slightly bouncy frictionless material. Its restitution
+// combines with the minimum, so bodies bounce off walls less than off each other.
+func DefaultStaticPhysicsMaterial() PhysicsMaterial {
+	return PhysicsMaterial{
+		Restitution:        0.3,
+		RestitutionCombine: CombineMinimum,
+	}
+}
+
+func (p *PhysicsMaterial) Init() {
+	*p = DefaultPhysicsMaterial()
+}
+
+func (p *PhysicsMaterial) Reset() {
+	*p = DefaultPhysicsMaterial()
+}
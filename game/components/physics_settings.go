@@ -0,0 +1,54 @@
+package components
+
+import (
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+func init() {
+	ecs.RegisterComponent[PhysicsSettings]()
+	ecs.RegisterComponent[Interpolation]()
+}
+
+var _ ecs.Component = (*PhysicsSettings)(nil)
+
+// PhysicsSettings configures the fixed physics time step of a world.
+// Zero values keep the defaults of the physics systems.
+type PhysicsSettings struct {
+	// TickRate is the number of physics steps per second of game time.
+	TickRate float64
+	// MaxSubSteps limits the steps run in a single frame, time beyond it is dropped
+	// so a slow frame can not snowball into ever slower ones.
+	MaxSubSteps int
+}
+
+func (p *PhysicsSettings) Init() {
+	p.TickRate = 0
+	p.MaxSubSteps = 0
+}
+
+func (p *PhysicsSettings) Reset() {
+	p.TickRate = 0
+	p.MaxSubSteps = 0
+}
+
+var _ ecs.Component = (*Interpolation)(nil)
+
+// Interpolation is the render state of a physics body that moves in fixed steps.
+// Position and Rotation are blended between the previous step and the current Transform
+// by how far the frame is into the next step.
+type Interpolation struct {
+	PreviousPosition cp.Vector
+	PreviousRotation float64
+
+	Position cp.Vector
+	Rotation float64
+}
+
+func (i *Interpolation) Init() {
+	*i = Interpolation{}
+}
+
+func (i *Interpolation) Reset() {
+	*i = Interpolation{}
+}
@@ -4,6 +4,7 @@ import ecs "github.com/samix73/ebiten-ecs"
 
 func init() {
 	ecs.RegisterComponent[Player]()
+	ecs.RegisterComponent[PlayerMovement]()
 }
 
 var _ ecs.Component = (*Player)(nil)
@@ -13,3 +14,20 @@ type Player struct{}
 func (p *Player) Init() {}
 
 func (p *Player) Reset() {}
+
+var _ ecs.Component = (*PlayerMovement)(nil)
+
+// PlayerMovement lets a Player walk in the direction of the held movement keys,
+// for worlds seen from the top such as mazes. Players without it jump instead.
+type PlayerMovement struct {
+	// Speed is the walking speed in units per second.
+	Speed float64
+}
+
+func (p *PlayerMovement) Init() {
+	p.Speed = 0
+}
+
+func (p *PlayerMovement) Reset() {
+	p.Speed = 0
+}
@@ -15,7 +15,8 @@ func init() {
 
 // Renderable represents a 2D entity that can be rendered on the screen.
 type Renderable struct {
-	Order      int // Rendering order; lower values are rendered first
+	Order      int    // Rendering order within the layer; lower values are rendered first
+	Layer      string // Name of the RenderLayer, RenderLayerEntities when empty
 	SpritePath string
 	Sprite     *ebiten.Image `toml:"-"`
 	GeoM       ebiten.GeoM   `toml:"-"`
@@ -29,6 +30,9 @@ func (r *Renderable) UnmarshalTOML(data any) error {
 
 	r.SpritePath = d["SpritePath"].(string)
 	r.Order = int(d["Order"].(int64))
+	if layer, ok := d["Layer"].(string); ok {
+		r.Layer = layer
+	}
 
 	var err error
 	r.Sprite, err = assets.GetSprite(r.SpritePath)
@@ -45,6 +49,7 @@ func (r *Renderable) Reset() {
 	}
 	r.GeoM.Reset()
 	r.Order = 0
+	r.Layer = ""
 }
 
 // Render marks entities to be rendered.
@@ -0,0 +1,81 @@
+package components
+
+import (
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+func init() {
+	ecs.RegisterComponent[RenderLayers]()
+}
+
+// Names of the default render layers.
+const (
+	RenderLayerBackground = "background"
+	RenderLayerTiles      = "tiles"
+	RenderLayerEntities   = "entities"
+	RenderLayerUI         = "ui"
+)
+
+// RenderLayer is a named group of sprites that are drawn together.
+type RenderLayer struct {
+	Name string
+	// Order is the drawing order of layers; lower values are drawn first.
+	Order int
+	// Parallax scales how far the layer scrolls when the camera moves, 1 when zero.
+	// Distant backgrounds use values below 1, such as 0.5 to scroll at half speed.
+	Parallax float64
+	// ScreenSpace layers ignore the camera's position, zoom and rotation. The position of
+	// their sprites is in pixels from the center of the viewport, with y pointing up.
+	ScreenSpace bool
+	// Offscreen layers are drawn into an image of their own, which is then drawn
+	// onto the viewport with Opacity.
+	Offscreen bool
+	// Opacity of an offscreen layer, 1 when zero.
+	Opacity float64
+}
+
+// ParallaxFactor returns Parallax, 1 when zero.
+func (l RenderLayer) ParallaxFactor() float64 {
+	if l.Parallax == 0 {
+		return 1
+	}
+
+	return l.Parallax
+}
+
+// OpacityFactor returns Opacity capped at 1, 1 when not positive.
+func (l RenderLayer) OpacityFactor() float64 {
+	if l.Opacity <= 0 {
+		return 1
+	}
+
+	return min(l.Opacity, 1)
+}
+
+// DefaultRenderLayers are the layers of worlds that don't declare their own.
+func DefaultRenderLayers() []RenderLayer {
+	return []RenderLayer{
+		{Name: RenderLayerBackground, Order: 0},
+		{Name: RenderLayerTiles, Order: 10},
+		{Name: RenderLayerEntities, Order: 20},
+		{Name: RenderLayerUI, Order: 30, ScreenSpace: true},
+	}
+}
+
+var _ ecs.Component = (*RenderLayers)(nil)
+
+// RenderLayers declares the render layers of a world. Worlds add it through a settings entity,
+// CameraSystem uses DefaultRenderLayers without one.
+// A Renderable whose Layer is not declared is drawn in the entities layer,
+// or in the first layer when the world does not declare one.
+type RenderLayers struct {
+	Layers []RenderLayer
+}
+
+func (r *RenderLayers) Init() {
+	r.Layers = r.Layers[:0]
+}
+
+func (r *RenderLayers) Reset() {
+	r.Layers = r.Layers[:0]
+}
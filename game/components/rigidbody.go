@@ -9,16 +9,69 @@ func init() {
 	ecs.RegisterComponent[RigidBody]()
 }
 
+// BodyType decides how a RigidBody is moved by the physics systems.
+type BodyType uint8
+
+const (
+	// BodyDynamic bodies are moved by gravity, their velocity and collisions.
+	BodyDynamic BodyType = iota
+	// BodyKinematic bodies only move by their velocity. They push dynamic bodies
+	// without being pushed back, like moving platforms or scripted obstacles.
+	BodyKinematic
+	// BodyStatic bodies never move, like colliders without a RigidBody.
+	BodyStatic
+)
+
+var bodyTypeNames = newTextEnum("BodyType", map[BodyType]string{
+	BodyDynamic:   "dynamic",
+	BodyKinematic: "kinematic",
+	BodyStatic:    "static",
+})
+
+func (b BodyType) String() string {
+	return bodyTypeNames.String(b)
+}
+
+func (b BodyType) MarshalText() ([]byte, error) {
+	return bodyTypeNames.MarshalText(b)
+}
+
+// UnmarshalText decodes a body type by its case-insensitive name, so entity TOML can use
+// Type = "kinematic".
+func (b *BodyType) UnmarshalText(text []byte) error {
+	return bodyTypeNames.UnmarshalText(b, text)
+}
+
 // RigidBody represents a physics body with mass, velocity, and gravity.
 type RigidBody struct {
+	Type     BodyType
 	Mass     float64
 	Velocity cp.Vector
 	Gravity  bool
+	// GravityScale multiplies the gravity applied to the body, 1 when zero.
+	// Bodies without gravity turn Gravity off.
+	GravityScale float64
+	// Bullet enables continuous collision detection against static colliders,
+	// for bodies fast enough to pass through a collider between two updates.
+	Bullet bool
+}
+
+// GravityMultiplier returns the factor applied to gravity,
+// 0 when Gravity is disabled or the body is not dynamic.
+func (r *RigidBody) GravityMultiplier() float64 {
+	switch {
+	case !r.Gravity || r.Type != BodyDynamic:
+		return 0
+	case r.GravityScale == 0:
+		return 1
+	default:
+		return r.GravityScale
+	}
 }
 
-// ApplyImpulse applies an impulse to the rigid body.
+// ApplyImpulse applies an impulse to the rigid body, only dynamic bodies respond to impulses.
 func (r *RigidBody) ApplyImpulse(impulse cp.Vector) {
-	if r.Mass <= 0 {
+	if r.Mass <= 0 || r.Type != BodyDynamic {
 		return
 	}
 
@@ -32,15 +85,21 @@ func (rb *RigidBody) ApplyAcceleration(acceleration cp.Vector) {
 }
 
 func (r *RigidBody) Init() {
+	r.Type = BodyDynamic
 	r.Mass = 1.0
 	r.Velocity.X = 0
 	r.Velocity.Y = 0
 	r.Gravity = true
+	r.GravityScale = 0
+	r.Bullet = false
 }
 
 func (r *RigidBody) Reset() {
+	r.Type = BodyDynamic
 	r.Mass = 1.0
 	r.Velocity.X = 0
 	r.Velocity.Y = 0
 	r.Gravity = false
+	r.GravityScale = 0
+	r.Bullet = false
 }
@@ -0,0 +1,203 @@
+package components
+
+import ecs "github.com/samix73/ebiten-ecs"
+
+func init() {
+	ecs.RegisterComponent[SpriteAnimation]()
+}
+
+// AnimationMode decides what a clip does after its last frame.
+type AnimationMode uint8
+
+const (
+	// AnimationLoop starts over from the first frame.
+	AnimationLoop AnimationMode = iota
+	// AnimationOnce stops on the last frame, or plays the clip's Next clip.
+	AnimationOnce
+	// AnimationPingPong plays the frames backwards, then forwards again.
+	AnimationPingPong
+)
+
+var animationModeNames = newTextEnum("AnimationMode", map[AnimationMode]string{
+	AnimationLoop:     "loop",
+	AnimationOnce:     "once",
+	AnimationPingPong: "pingpong",
+})
+
+func (m AnimationMode) String() string {
+	return animationModeNames.String(m)
+}
+
+func (m AnimationMode) MarshalText() ([]byte, error) {
+	return animationModeNames.MarshalText(m)
+}
+
+// UnmarshalText decodes a mode by its case-insensitive name, so entity TOML can use
+// Mode = "pingpong".
+func (m *AnimationMode) UnmarshalText(text []byte) error {
+	return animationModeNames.UnmarshalText(m, text)
+}
+
+// AnimationClip is a named sequence of sprite sheet frames.
+type AnimationClip struct {
+	Name string
+	// Frames are indices into the sprite sheet, counted left to right and top to bottom.
+	Frames []int
+	// FPS is the number of frames per second, the animation's FPS when zero.
+	FPS float64
+	// Durations are the seconds each of the Frames is shown, such as the frame durations
+	// of an Aseprite sheet. Frames without a duration are shown for 1/FPS seconds.
+	Durations []float64
+	Mode      AnimationMode
+	// Next is the clip played once an AnimationOnce clip has finished.
+	Next string
+}
+
+var _ ecs.Component = (*SpriteAnimation)(nil)
+
+// SpriteAnimation animates the Renderable of an entity with frames of a sprite sheet.
+type SpriteAnimation struct {
+	// Sheet is the sprite sheet, loaded from the sprites directory. Frames of an Aseprite
+	// JSON sheet are its exported frames and its frame tags are the clips when Clips is empty.
+	Sheet       string
+	FrameWidth  int
+	FrameHeight int
+	// FPS is the number of frames per second of clips without their own.
+	FPS   float64
+	Clips []AnimationClip
+	// Clip is the name of the playing clip, the first clip when empty.
+	Clip string
+
+	// Frame is the position in the frames of the playing clip.
+	Frame int
+	// Elapsed is the time spent on the current frame, in seconds.
+	Elapsed float64
+	// Reverse is set while a ping-pong clip plays backwards.
+	Reverse bool
+	// Finished is set once an AnimationOnce clip reached its last frame.
+	Finished bool
+}
+
+// CurrentClip returns the playing clip.
+func (a *SpriteAnimation) CurrentClip() (AnimationClip, bool) {
+	if len(a.Clips) == 0 {
+		return AnimationClip{}, false
+	}
+
+	if a.Clip == "" {
+		return a.Clips[0], true
+	}
+
+	for _, clip := range a.Clips {
+		if clip.Name == a.Clip {
+			return clip, true
+		}
+	}
+
+	return AnimationClip{}, false
+}
+
+// Play starts the named clip from its first frame, restarting it if it is already playing.
+func (a *SpriteAnimation) Play(name string) {
+	a.Clip = name
+	a.Frame = 0
+	a.Elapsed = 0
+	a.Reverse = false
+	a.Finished = false
+}
+
+// SheetFrame returns the sprite sheet frame to draw.
+func (a *SpriteAnimation) SheetFrame() (int, bool) {
+	clip, ok := a.CurrentClip()
+	if !ok || len(clip.Frames) == 0 {
+		return 0, false
+	}
+
+	return clip.Frames[min(max(a.Frame, 0), len(clip.Frames)-1)], true
+}
+
+// frameTime returns the seconds the current frame of clip is shown, zero when it never changes.
+func (a *SpriteAnimation) frameTime(clip AnimationClip) float64 {
+	if a.Frame >= 0 && a.Frame < len(clip.Durations) && clip.Durations[a.Frame] > 0 {
+		return clip.Durations[a.Frame]
+	}
+
+	fps := clip.FPS
+	if fps <= 0 {
+		fps = a.FPS
+	}
+	if fps <= 0 {
+		return 0
+	}
+
+	return 1 / fps
+}
+
+// Advance moves the animation forward by dt seconds.
+func (a *SpriteAnimation) Advance(dt float64) {
+	clip, ok := a.CurrentClip()
+	if !ok || len(clip.Frames) == 0 || a.Finished {
+		return
+	}
+
+	if a.frameTime(clip) <= 0 {
+		return
+	}
+
+	a.Elapsed += dt
+
+	for frameTime := a.frameTime(clip); frameTime > 0 && a.Elapsed >= frameTime; frameTime = a.frameTime(clip) {
+		a.Elapsed -= frameTime
+		a.step(clip)
+
+		if a.Finished {
+			if clip.Next != "" {
+				a.Play(clip.Next)
+			}
+
+			return
+		}
+	}
+}
+
+// step moves to the next frame of clip.
+func (a *SpriteAnimation) step(clip AnimationClip) {
+	last := len(clip.Frames) - 1
+
+	switch clip.Mode {
+	case AnimationOnce:
+		if a.Frame >= last {
+			a.Frame = last
+			a.Finished = true
+			return
+		}
+
+		a.Frame++
+	case AnimationPingPong:
+		if last == 0 {
+			return
+		}
+
+		if a.Frame >= last {
+			a.Reverse = true
+		} else if a.Frame <= 0 {
+			a.Reverse = false
+		}
+
+		if a.Reverse {
+			a.Frame--
+		} else {
+			a.Frame++
+		}
+	default:
+		a.Frame = (a.Frame + 1) % len(clip.Frames)
+	}
+}
+
+func (a *SpriteAnimation) Init() {
+	*a = SpriteAnimation{}
+}
+
+func (a *SpriteAnimation) Reset() {
+	*a = SpriteAnimation{}
+}
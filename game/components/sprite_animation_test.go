@@ -0,0 +1,76 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testAnimation(mode AnimationMode, frames ...int) *SpriteAnimation {
+	return &SpriteAnimation{
+		FPS:   10,
+		Clips: []AnimationClip{{Name: "clip", Frames: frames, Mode: mode}},
+	}
+}
+
+// playedFrames advances the animation by one frame at a time and returns the frames shown.
+func playedFrames(a *SpriteAnimation, count int) []int {
+	frames := make([]int, 0, count)
+	for range count {
+		a.Advance(0.1)
+		frame, _ := a.SheetFrame()
+		frames = append(frames, frame)
+	}
+
+	return frames
+}
+
+func TestSpriteAnimation_Modes(t *testing.T) {
+	require.Equal(t, []int{11, 12, 10, 11, 12}, playedFrames(testAnimation(AnimationLoop, 10, 11, 12), 5))
+	require.Equal(t, []int{11, 12, 11, 10, 11, 12}, playedFrames(testAnimation(AnimationPingPong, 10, 11, 12), 6))
+
+	once := testAnimation(AnimationOnce, 10, 11, 12)
+	require.Equal(t, []int{11, 12, 12, 12}, playedFrames(once, 4))
+	require.True(t, once.Finished)
+}
+
+func TestSpriteAnimation_Advance(t *testing.T) {
+	a := testAnimation(AnimationLoop, 0, 1, 2, 3)
+
+	// Time below a frame accumulates, time over several frames skips them.
+	a.Advance(0.05)
+	require.Zero(t, a.Frame)
+	a.Advance(0.26)
+	require.Equal(t, 3, a.Frame)
+	require.InDelta(t, 0.01, a.Elapsed, 1e-9)
+
+	a.Clips[0].FPS = 100
+	a.Advance(0.01)
+	require.Zero(t, a.Frame)
+}
+
+func TestSpriteAnimation_Next(t *testing.T) {
+	a := &SpriteAnimation{
+		FPS: 10,
+		Clips: []AnimationClip{
+			{Name: "idle", Frames: []int{0}},
+			{Name: "flap", Frames: []int{1, 2}, Mode: AnimationOnce, Next: "idle"},
+		},
+	}
+
+	a.Play("flap")
+	frame, ok := a.SheetFrame()
+	require.True(t, ok)
+	require.Equal(t, 1, frame)
+
+	a.Advance(0.1)
+	require.Equal(t, "flap", a.Clip)
+
+	a.Advance(0.1)
+	require.Equal(t, "idle", a.Clip)
+	require.False(t, a.Finished)
+
+	a.Play("missing")
+	_, ok = a.SheetFrame()
+	require.False(t, ok)
+}
@@ -9,15 +9,19 @@ import (
 )
 
 const (
-	tileSize = 16
+	DefaultTileSize = 16
 )
 
-func NewTileMapEntity(em *ecs.EntityManager, img *ebiten.Image, layer, width, height int, tiles []int) (ecs.EntityID, error) {
+func NewTileMapEntity(em *ecs.EntityManager, img *ebiten.Image, layer, tileSize, width, height int, tiles []int) (ecs.EntityID, error) {
 	entityID, err := em.NewEntity()
 	if err != nil {
 		return 0, fmt.Errorf("error creating entity: %w", err)
 	}
 
+	if tileSize <= 0 {
+		tileSize = DefaultTileSize
+	}
+
 	ecs.AddComponent[components.Transform](em, entityID)
 	ecs.AddComponent[components.Renderable](em, entityID)
 	tileMap, err := ecs.AddComponent[components.TileMap](em, entityID)
@@ -0,0 +1,103 @@
+package solver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samix73/game/game/components"
+)
+
+// Metrics describes the layout of a maze and how hard it is to solve.
+type Metrics struct {
+	OpenCells int
+	// PathLength is the number of moves on the shortest path from entry to exit.
+	PathLength int
+	// DeadEnds counts open cells with a single open neighbour, excluding entry and exit.
+	DeadEnds int
+	// Junctions counts open cells with three or more open neighbours.
+	Junctions int
+	// BranchingFactor is the average number of open neighbours of a junction.
+	BranchingFactor float64
+	// Decisions counts the junctions on the shortest path.
+	Decisions int
+	// PathRatio is PathLength divided by the Manhattan distance from entry to exit.
+	PathRatio float64
+	Connected bool
+	Solvable  bool
+	// Difficulty is PathRatio multiplied by one more than Decisions.
+	// A straight corridor scores 1; unsolvable mazes score 0.
+	Difficulty float64
+}
+
+// Analyze solves the maze and computes its metrics.
+func Analyze(m *components.Maze) Metrics {
+	entry := Point{X: int(m.Entry.X), Y: int(m.Entry.Y)}
+	exit := Point{X: int(m.Exit.X), Y: int(m.Exit.Y)}
+
+	var metrics Metrics
+	branches := 0
+
+	for y := range m.Height {
+		for x := range m.Width {
+			if m.IsWall(x, y) {
+				continue
+			}
+
+			p := Point{X: x, Y: y}
+			metrics.OpenCells++
+
+			switch n := len(Neighbors(m, p)); {
+			case n == 1 && p != entry && p != exit:
+				metrics.DeadEnds++
+			case n >= 3:
+				metrics.Junctions++
+				branches += n
+			}
+		}
+	}
+
+	if metrics.Junctions > 0 {
+		metrics.BranchingFactor = float64(branches) / float64(metrics.Junctions)
+	}
+
+	metrics.Connected = Connected(m)
+
+	path, err := Solve(m)
+	if err != nil {
+		return metrics
+	}
+
+	metrics.Solvable = true
+	metrics.PathLength = len(path) - 1
+
+	for _, p := range path {
+		if len(Neighbors(m, p)) >= 3 {
+			metrics.Decisions++
+		}
+	}
+
+	if distance := manhattan(entry, exit); distance > 0 {
+		metrics.PathRatio = float64(metrics.PathLength) / float64(distance)
+	}
+
+	metrics.Difficulty = metrics.PathRatio * float64(1+metrics.Decisions)
+
+	return metrics
+}
+
+func (m Metrics) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Solvable:         %t\n", m.Solvable)
+	fmt.Fprintf(&b, "Connected:        %t\n", m.Connected)
+	fmt.Fprintf(&b, "Open cells:       %d\n", m.OpenCells)
+	fmt.Fprintf(&b, "Path length:      %d\n", m.PathLength)
+	fmt.Fprintf(&b, "Path ratio:       %.2f\n", m.PathRatio)
+	fmt.Fprintf(&b, "Dead ends:        %d\n", m.DeadEnds)
+	fmt.Fprintf(&b, "Junctions:        %d\n", m.Junctions)
+	fmt.Fprintf(&b, "Branching factor: %.2f\n", m.BranchingFactor)
+	fmt.Fprintf(&b, "Decisions:        %d\n", m.Decisions)
+	fmt.Fprintf(&b, "Difficulty:       %.2f\n", m.Difficulty)
+
+	return b.String()
+}
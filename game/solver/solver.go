@@ -0,0 +1,177 @@
+// Package solver finds paths through mazes and measures how hard they are.
+package solver
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+
+	"github.com/samix73/game/game/components"
+)
+
+var ErrUnsolvable = errors.New("maze has no path from entry to exit")
+
+// Point is a cell in maze grid coordinates.
+type Point struct {
+	X, Y int
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("%d,%d", p.X, p.Y)
+}
+
+var directions = [4]Point{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}}
+
+// Neighbors returns the open cells adjacent to p.
+func Neighbors(m *components.Maze, p Point) []Point {
+	neighbors := make([]Point, 0, 4)
+	for _, d := range directions {
+		n := Point{X: p.X + d.X, Y: p.Y + d.Y}
+		if m.IsWall(n.X, n.Y) {
+			continue
+		}
+
+		neighbors = append(neighbors, n)
+	}
+
+	return neighbors
+}
+
+// Solve returns the shortest path from the maze entry to its exit, both inclusive.
+func Solve(m *components.Maze) ([]Point, error) {
+	entry := Point{X: int(m.Entry.X), Y: int(m.Entry.Y)}
+	exit := Point{X: int(m.Exit.X), Y: int(m.Exit.Y)}
+
+	path, ok := ShortestPath(m, entry, exit)
+	if !ok {
+		return nil, ErrUnsolvable
+	}
+
+	return path, nil
+}
+
+// ShortestPath finds the shortest path between two open cells using A* with a Manhattan heuristic.
+// The returned path includes both from and to.
+func ShortestPath(m *components.Maze, from, to Point) ([]Point, bool) {
+	if m.IsWall(from.X, from.Y) || m.IsWall(to.X, to.Y) {
+		return nil, false
+	}
+
+	cost := map[Point]int{from: 0}
+	parent := make(map[Point]Point)
+
+	open := &frontier{}
+	heap.Push(open, node{point: from, priority: manhattan(from, to)})
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(node).point
+		if current == to {
+			return backtrack(parent, from, to), true
+		}
+
+		for _, n := range Neighbors(m, current) {
+			c := cost[current] + 1
+			if prev, seen := cost[n]; seen && prev <= c {
+				continue
+			}
+
+			cost[n] = c
+			parent[n] = current
+			heap.Push(open, node{point: n, priority: c + manhattan(n, to)})
+		}
+	}
+
+	return nil, false
+}
+
+// Reachable returns every open cell reachable from start using a breadth-first search.
+func Reachable(m *components.Maze, start Point) map[Point]bool {
+	reached := make(map[Point]bool)
+	if m.IsWall(start.X, start.Y) {
+		return reached
+	}
+
+	reached[start] = true
+	queue := []Point{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, n := range Neighbors(m, current) {
+			if reached[n] {
+				continue
+			}
+
+			reached[n] = true
+			queue = append(queue, n)
+		}
+	}
+
+	return reached
+}
+
+// Connected reports whether every open cell of the maze is reachable from its entry.
+func Connected(m *components.Maze) bool {
+	reached := Reachable(m, Point{X: int(m.Entry.X), Y: int(m.Entry.Y)})
+
+	for y := range m.Height {
+		for x := range m.Width {
+			if !m.IsWall(x, y) && !reached[Point{X: x, Y: y}] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func manhattan(a, b Point) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+func backtrack(parent map[Point]Point, from, to Point) []Point {
+	path := []Point{to}
+	for current := to; current != from; {
+		current = parent[current]
+		path = append(path, current)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+type node struct {
+	point    Point
+	priority int
+}
+
+// frontier is a min-heap of nodes ordered by priority.
+type frontier []node
+
+func (f frontier) Len() int           { return len(f) }
+func (f frontier) Less(i, j int) bool { return f[i].priority < f[j].priority }
+func (f frontier) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+
+func (f *frontier) Push(x any) {
+	*f = append(*f, x.(node))
+}
+
+func (f *frontier) Pop() any {
+	old := *f
+	n := old[len(old)-1]
+	*f = old[:len(old)-1]
+
+	return n
+}
@@ -0,0 +1,129 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	"github.com/samix73/game/game/components"
+	"github.com/stretchr/testify/require"
+)
+
+func testMaze(t *testing.T, rows ...string) *components.Maze {
+	t.Helper()
+
+	m := &components.Maze{
+		Width:  len(rows[0]),
+		Height: len(rows),
+		Cells:  make([][]uint8, len(rows)),
+	}
+
+	for y, row := range rows {
+		require.Len(t, row, m.Width)
+
+		m.Cells[y] = make([]uint8, m.Width)
+		for x, c := range row {
+			switch c {
+			case 'S':
+				m.Entry = cp.Vector{X: float64(x), Y: float64(y)}
+			case 'E':
+				m.Exit = cp.Vector{X: float64(x), Y: float64(y)}
+			}
+
+			if c != '#' {
+				m.Cells[y][x] = components.MazeFloor
+			}
+		}
+	}
+
+	return m
+}
+
+func TestSolve_ShortestPath(t *testing.T) {
+	m := testMaze(t,
+		"S..#",
+		".#..",
+		"...E",
+	)
+
+	path, err := Solve(m)
+	require.NoError(t, err)
+	require.Len(t, path, 6)
+	require.Equal(t, Point{X: 0, Y: 0}, path[0])
+	require.Equal(t, Point{X: 3, Y: 2}, path[len(path)-1])
+
+	for i := 1; i < len(path); i++ {
+		require.Equal(t, 1, manhattan(path[i-1], path[i]))
+	}
+}
+
+func TestSolve_Unsolvable(t *testing.T) {
+	m := testMaze(t,
+		"S.#..",
+		"..#.E",
+	)
+
+	_, err := Solve(m)
+	require.ErrorIs(t, err, ErrUnsolvable)
+	require.False(t, Connected(m))
+
+	metrics := Analyze(m)
+	require.False(t, metrics.Solvable)
+	require.Zero(t, metrics.Difficulty)
+}
+
+func TestAnalyze_Corridor(t *testing.T) {
+	m := testMaze(t, "S...E")
+
+	metrics := Analyze(m)
+	require.True(t, metrics.Solvable)
+	require.True(t, metrics.Connected)
+	require.Equal(t, 4, metrics.PathLength)
+	require.Equal(t, 0, metrics.DeadEnds)
+	require.Equal(t, 0, metrics.Junctions)
+	require.Equal(t, 1.0, metrics.PathRatio)
+	require.Equal(t, 1.0, metrics.Difficulty)
+}
+
+func TestAnalyze_DeadEndsAndJunctions(t *testing.T) {
+	m := testMaze(t,
+		"S...E",
+		"##.##",
+		"##.##",
+	)
+
+	metrics := Analyze(m)
+	require.True(t, metrics.Solvable)
+	require.Equal(t, 1, metrics.DeadEnds)
+	require.Equal(t, 1, metrics.Junctions)
+	require.Equal(t, 3.0, metrics.BranchingFactor)
+	require.Equal(t, 1, metrics.Decisions)
+	require.Equal(t, 2.0, metrics.Difficulty)
+}
+
+func TestAnalyze_GeneratedMazes(t *testing.T) {
+	entry := cp.Vector{X: 0, Y: 0}
+	exit := cp.Vector{X: 20, Y: 14}
+
+	for _, algorithm := range components.MazeAlgorithms() {
+		t.Run(string(algorithm), func(t *testing.T) {
+			m, err := components.GenerateMaze(21, 15, entry, exit, 42, 1, components.MazeOptions{Algorithm: algorithm})
+			require.NoError(t, err)
+
+			metrics := Analyze(&m)
+			require.True(t, metrics.Solvable)
+			require.True(t, metrics.Connected)
+			require.Positive(t, metrics.DeadEnds)
+
+			again, err := components.GenerateMaze(21, 15, entry, exit, 42, 1, components.MazeOptions{Algorithm: algorithm})
+			require.NoError(t, err)
+			require.Equal(t, m.Cells, again.Cells)
+
+			braided, err := components.GenerateMaze(21, 15, entry, exit, 42, 1, components.MazeOptions{Algorithm: algorithm, Braid: 1})
+			require.NoError(t, err)
+
+			metrics = Analyze(&braided)
+			require.True(t, metrics.Solvable)
+			require.Zero(t, metrics.DeadEnds)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package systems
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/assets"
+	"github.com/samix73/game/game/components"
+)
+
+var _ ecs.System = (*AnimationSystem)(nil)
+
+func init() {
+	ecs.RegisterSystem(NewAnimationSystem)
+}
+
+// AnimationSystem advances SpriteAnimations with the game's scaled delta time and shows
+// their current frame in the entity's Renderable. Worlds list it before CameraSystem.
+type AnimationSystem struct {
+	*ecs.BaseSystem
+}
+
+func NewAnimationSystem(priority int) *AnimationSystem {
+	return &AnimationSystem{
+		BaseSystem: ecs.NewBaseSystem(priority),
+	}
+}
+
+// frameBounds returns the bounds of a frame in a sheet of the given width,
+// frames are counted left to right and top to bottom.
+func frameBounds(sheetWidth, frameWidth, frameHeight, frame int) image.Rectangle {
+	columns := max(sheetWidth/frameWidth, 1)
+	x := (frame % columns) * frameWidth
+	y := (frame / columns) * frameHeight
+
+	return image.Rect(x, y, x+frameWidth, y+frameHeight)
+}
+
+func (a *AnimationSystem) Update() error {
+	em := a.EntityManager()
+	dt := a.Game().DeltaTime()
+
+	for _, entity := range ecs.Query2[components.SpriteAnimation, components.Renderable](em) {
+		animation := ecs.MustGetComponent[components.SpriteAnimation](em, entity)
+
+		var aseprite *assets.AsepriteSheet
+		if assets.IsAseprite(animation.Sheet) {
+			var err error
+			if aseprite, err = assets.GetAseprite(animation.Sheet); err != nil {
+				return fmt.Errorf("systems.AnimationSystem.Update: %w", err)
+			}
+
+			animation.LoadAseprite(aseprite)
+		}
+
+		animation.Advance(dt)
+
+		frame, ok := animation.SheetFrame()
+		if !ok || animation.Sheet == "" {
+			continue
+		}
+
+		sprite, err := a.frameSprite(animation, aseprite, frame)
+		if err != nil {
+			return fmt.Errorf("systems.AnimationSystem.Update: %w", err)
+		}
+
+		if sprite != nil {
+			ecs.MustGetComponent[components.Renderable](em, entity).Sprite = sprite
+		}
+	}
+
+	return nil
+}
+
+// frameSprite returns the sprite of a sheet frame, an exported frame of Aseprite sheets and
+// a cell of the FrameWidth by FrameHeight grid otherwise. It returns nil without a grid.
+func (a *AnimationSystem) frameSprite(animation *components.SpriteAnimation, aseprite *assets.AsepriteSheet, frame int) (*ebiten.Image, error) {
+	if aseprite != nil {
+		return aseprite.Frame(frame)
+	}
+
+	if animation.FrameWidth <= 0 || animation.FrameHeight <= 0 {
+		return nil, nil
+	}
+
+	sheet, err := assets.GetSprite(animation.Sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := frameBounds(sheet.Bounds().Dx(), animation.FrameWidth, animation.FrameHeight, frame)
+
+	return assets.SubSprite(sheet, bounds), nil
+}
+
+func (a *AnimationSystem) Start() error {
+	return nil
+}
+
+func (a *AnimationSystem) Teardown() {
+}
@@ -2,6 +2,7 @@ package systems
 
 import (
 	"fmt"
+	"image"
 	"log/slog"
 	"slices"
 
@@ -23,6 +24,30 @@ type CameraSystem struct {
 	*ecs.BaseSystem
 
 	activeCamera ecs.EntityID
+
+	// views, layers, offscreen images and batch are reused every frame to keep drawing allocation free.
+	views      []*cameraView
+	layers     []components.RenderLayer
+	layerIndex map[string]int
+	offscreen  map[offscreenKey]*ebiten.Image
+	batch      spriteBatch
+}
+
+// spriteDraw is a sprite a camera draws this frame.
+type spriteDraw struct {
+	entity ecs.EntityID
+	layer  int
+	order  int
+	geoM   ebiten.GeoM
+}
+
+// cameraView is what a camera draws this frame.
+type cameraView struct {
+	camera   ecs.EntityID
+	viewport image.Rectangle
+	// layers holds the projection of each render layer, indexed like CameraSystem.layers.
+	layers []layerView
+	draws  []spriteDraw
 }
 
 func NewCameraSystem(priority int) *CameraSystem {
@@ -75,64 +100,135 @@ func (c *CameraSystem) getActiveCamera() (ecs.EntityID, error) {
 	return activeCamera, nil
 }
 
-// inView checks if the entity is within the camera's view and returns its on-screen position if it is.
-func (c *CameraSystem) inView(cameraTransform *components.Transform, entityTransform *components.Transform, sprite *ebiten.Image) (cp.Vector, bool) {
+// renderPosition returns where an entity is drawn, between its last two physics steps
+// for interpolated bodies and at its Transform otherwise.
+func (c *CameraSystem) renderPosition(em *ecs.EntityManager, entity ecs.EntityID) cp.Vector {
+	if interpolation, ok := ecs.GetComponent[components.Interpolation](em, entity); ok {
+		return interpolation.Position
+	}
+
+	return ecs.MustGetComponent[components.Transform](em, entity).Position
+}
+
+// renderRotation returns the rotation an entity is drawn with, see renderPosition.
+func (c *CameraSystem) renderRotation(em *ecs.EntityManager, entity ecs.EntityID) float64 {
+	if interpolation, ok := ecs.GetComponent[components.Interpolation](em, entity); ok {
+		return interpolation.Rotation
+	}
+
+	return ecs.MustGetComponent[components.Transform](em, entity).Rotation
+}
+
+// spriteGeoM places a sprite on the screen, rotated and scaled about its center.
+// rotation is relative to the camera and counter-clockwise like Transform.Rotation.
+func spriteGeoM(sprite *ebiten.Image, center cp.Vector, rotation, zoom float64) ebiten.GeoM {
+	var geoM ebiten.GeoM
+	geoM.Translate(-float64(sprite.Bounds().Dx())/2, -float64(sprite.Bounds().Dy())/2)
+	geoM.Scale(zoom, zoom)
+	// Screen y points down, so a counter-clockwise rotation is negative on screen.
+	geoM.Rotate(-rotation)
+	geoM.Translate(center.X, center.Y)
+
+	return geoM
+}
+
+// inView checks if a sprite drawn with geoM overlaps the viewport.
+func inView(sprite *ebiten.Image, geoM ebiten.GeoM, viewport image.Rectangle) bool {
 	if sprite == nil {
-		return cp.Vector{}, false
+		return false
 	}
 
-	camX, camY := cameraTransform.Position.X, cameraTransform.Position.Y
-	entX, entY := entityTransform.Position.X, entityTransform.Position.Y
+	spriteBounds := cp.BB{R: float64(sprite.Bounds().Dx()), T: float64(sprite.Bounds().Dy())}
 
-	sw := float64(sprite.Bounds().Dx())
-	sh := float64(sprite.Bounds().Dy())
+	// AABB of the rotated sprite vs viewport rect
+	bounds := components.TransformedBounds(geoM, spriteBounds)
 
-	cfg := c.Game().Config()
+	return bounds.R > float64(viewport.Min.X) && bounds.T > float64(viewport.Min.Y) &&
+		bounds.L < float64(viewport.Max.X) && bounds.B < float64(viewport.Max.Y)
+}
+
+// targetSize returns the size of the image a camera draws to.
+func (c *CameraSystem) targetSize(camera *components.Camera) (int, int) {
+	if camera.Target != nil {
+		return camera.Target.Bounds().Dx(), camera.Target.Bounds().Dy()
+	}
 
-	screenWidth := float64(cfg.ScreenWidth)
-	screenHeight := float64(cfg.ScreenHeight)
+	cfg := c.Game().Config()
 
-	halfScreenWidth := screenWidth / 2
-	halfScreenHeight := screenHeight / 2
+	return cfg.ScreenWidth, cfg.ScreenHeight
+}
 
-	// Compute sprite top-left in screen space.
-	screenX := (entX - camX) + halfScreenWidth - sw*0.5
-	screenY := halfScreenHeight - (entY - camY) - sh*0.5
+// updateViews moves the cameras and prepares an empty view for each of them, in drawing order.
+func (c *CameraSystem) updateViews(em *ecs.EntityManager) {
+	cameras := ecs.Query2[components.Camera, components.Transform](em)
+	slices.SortStableFunc(cameras, func(a, b ecs.EntityID) int {
+		return ecs.MustGetComponent[components.Camera](em, a).Order - ecs.MustGetComponent[components.Camera](em, b).Order
+	})
 
-	// AABB vs screen rect
-	if screenX+sw <= 0 || screenY+sh <= 0 || screenX >= screenWidth || screenY >= screenHeight {
-		return cp.Vector{}, false
+	if n := len(cameras); cap(c.views) < n {
+		c.views = slices.Grow(c.views[:cap(c.views)], n-cap(c.views))
 	}
+	c.views = c.views[:len(cameras)]
+	for i, camera := range cameras {
+		if c.views[i] == nil {
+			c.views[i] = &cameraView{}
+		}
+
+		c.follow(em, camera)
 
-	return cp.Vector{X: screenX, Y: screenY}, true
+		cameraComponent := ecs.MustGetComponent[components.Camera](em, camera)
+		view := c.views[i]
+		view.camera = camera
+		view.viewport = cameraComponent.ViewportRect(c.targetSize(cameraComponent))
+		c.layerViews(view, cameraComponent, c.renderPosition(em, camera), c.renderRotation(em, camera))
+		view.draws = view.draws[:0]
+	}
 }
 
 func (c *CameraSystem) Update() error {
 	em := c.EntityManager()
 
-	camera, err := c.getActiveCamera()
+	activeCamera, err := c.getActiveCamera()
 	if err != nil {
 		return fmt.Errorf("error getting active camera: %w", err)
 	}
 
-	cameraTransform := ecs.MustGetComponent[components.Transform](em, camera)
+	c.updateLayers(em)
+	c.updateViews(em)
 
 	for _, entity := range ecs.Query2[components.Transform, components.Renderable](em) {
-		entityTransform := ecs.MustGetComponent[components.Transform](em, entity)
+		entityPosition := c.renderPosition(em, entity)
 		render := ecs.MustGetComponent[components.Renderable](em, entity)
 		if render.Sprite == nil {
 			continue
 		}
 
-		onScreenPos, ok := c.inView(cameraTransform, entityTransform, render.Sprite)
+		entityRotation := c.renderRotation(em, entity)
+		layer := c.layerOf(render.Layer)
+
+		visible := false
+		for _, view := range c.views {
+			layerView := view.layers[layer]
+			x, y := layerView.projection.Apply(entityPosition.X, entityPosition.Y)
+			geoM := spriteGeoM(render.Sprite, cp.Vector{X: x, Y: y}, entityRotation-layerView.rotation, layerView.zoom)
+			if !inView(render.Sprite, geoM, view.viewport) {
+				continue
+			}
+
+			visible = true
+			view.draws = append(view.draws, spriteDraw{entity: entity, layer: layer, order: render.Order, geoM: geoM})
+
+			if view.camera == activeCamera {
+				render.GeoM = geoM
+			}
+		}
+
 		slog.Debug("Camera.Update",
-			slog.Bool("in_view", ok),
+			slog.Bool("in_view", visible),
 			slog.Uint64("entity", uint64(entity)),
-			slog.Any("position", entityTransform.Position),
-			slog.Any("on_screen_position", onScreenPos),
-			slog.Any("camera_position", cameraTransform.Position),
+			slog.Any("position", entityPosition),
 		)
-		if !ok {
+		if !visible {
 			ecs.RemoveComponent[components.Render](em, entity)
 
 			continue
@@ -143,37 +239,52 @@ func (c *CameraSystem) Update() error {
 				return fmt.Errorf("systems.CameraSystem.Update error adding render component: %w", err)
 			}
 		}
-
-		render.GeoM.SetElement(0, 2, onScreenPos.X)
-		render.GeoM.SetElement(1, 2, onScreenPos.Y)
 	}
 
 	return nil
 }
 
+// Draw draws every camera into its viewport, cameras with a higher Order are drawn on top.
+// Each camera draws the render layers in order, and the sprites of a layer by Renderable.Order.
 func (c *CameraSystem) Draw(screen *ebiten.Image) {
 	em := c.EntityManager()
 
-	renderables := make([]*components.Renderable, 0)
-
-	for _, entity := range ecs.Query2[components.Render, components.Renderable](em) {
-		renderable := ecs.MustGetComponent[components.Renderable](em, entity)
-
-		if renderable.Sprite == nil {
+	for _, view := range c.views {
+		// Components are looked up again, entities may have been removed since Update.
+		camera, ok := ecs.GetComponent[components.Camera](em, view.camera)
+		if !ok {
 			continue
 		}
 
-		renderables = append(renderables, renderable)
-	}
+		target := screen
+		if camera.Target != nil {
+			target = camera.Target
+		}
+		target = target.SubImage(view.viewport).(*ebiten.Image)
 
-	slices.SortStableFunc(renderables, func(a, b *components.Renderable) int {
-		return a.Order - b.Order
-	})
+		if camera.Background.A > 0 {
+			target.Fill(camera.Background)
+		}
+
+		slices.SortStableFunc(view.draws, func(a, b spriteDraw) int {
+			if a.layer != b.layer {
+				return a.layer - b.layer
+			}
 
-	for _, render := range renderables {
-		screen.DrawImage(render.Sprite, &ebiten.DrawImageOptions{
-			GeoM: render.GeoM,
+			return a.order - b.order
 		})
+
+		for start := 0; start < len(view.draws); {
+			layer := view.draws[start].layer
+
+			end := start
+			for end < len(view.draws) && view.draws[end].layer == layer {
+				end++
+			}
+
+			c.drawLayer(em, target, view, c.layers[layer], view.draws[start:end])
+			start = end
+		}
 	}
 }
 
@@ -182,4 +293,8 @@ func (c *CameraSystem) Start() error {
 }
 
 func (c *CameraSystem) Teardown() {
+	for key, img := range c.offscreen {
+		img.Deallocate()
+		delete(c.offscreen, key)
+	}
 }
@@ -0,0 +1,118 @@
+package systems
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/samix73/game/helpers"
+)
+
+// followTarget returns where a camera should look to follow its target.
+func (c *CameraSystem) followTarget(em *ecs.EntityManager, follow *components.CameraFollow) (cp.Vector, bool) {
+	target := follow.Target
+	if target == 0 {
+		player, ok := helpers.First(ecs.Query[components.Player](em))
+		if !ok {
+			return cp.Vector{}, false
+		}
+
+		target = player
+	}
+
+	if !ecs.HasComponent[components.Transform](em, target) {
+		return cp.Vector{}, false
+	}
+
+	position := c.renderPosition(em, target)
+	if rigidBody, ok := ecs.GetComponent[components.RigidBody](em, target); ok {
+		position = position.Add(rigidBody.Velocity.Mult(follow.LookAhead))
+	}
+
+	return position, true
+}
+
+// deadZoneGoal returns the closest camera position that has target inside the dead zone.
+func deadZoneGoal(position, target cp.Vector, deadZone cp.BB) cp.Vector {
+	offset := target.Sub(position)
+
+	goal := position
+	if offset.X < deadZone.L {
+		goal.X = target.X - deadZone.L
+	} else if offset.X > deadZone.R {
+		goal.X = target.X - deadZone.R
+	}
+
+	if offset.Y < deadZone.B {
+		goal.Y = target.Y - deadZone.B
+	} else if offset.Y > deadZone.T {
+		goal.Y = target.Y - deadZone.T
+	}
+
+	return goal
+}
+
+// smooth moves the camera from position towards goal over dt seconds.
+func smooth(follow *components.CameraFollow, position, goal cp.Vector, dt float64) cp.Vector {
+	if follow.Speed <= 0 {
+		follow.Velocity = cp.Vector{}
+		return goal
+	}
+
+	switch follow.Smoothing {
+	case components.SmoothingLerp:
+		return position.Lerp(goal, 1-math.Exp(-follow.Speed*dt))
+	case components.SmoothingSpring:
+		damping := follow.Damping
+		if damping <= 0 {
+			damping = 1
+		}
+
+		omega := follow.Speed
+		acceleration := goal.Sub(position).Mult(omega * omega).Sub(follow.Velocity.Mult(2 * damping * omega))
+		follow.Velocity = follow.Velocity.Add(acceleration.Mult(dt))
+
+		return position.Add(follow.Velocity.Mult(dt))
+	default:
+		return goal
+	}
+}
+
+// clampAxis keeps a view of the given half size inside [lower, upper] on one axis,
+// centering it when the view is larger than the range.
+func clampAxis(center, halfSize, lower, upper float64) float64 {
+	if upper-lower <= 2*halfSize {
+		return (lower + upper) / 2
+	}
+
+	return min(max(center, lower+halfSize), upper-halfSize)
+}
+
+// follow moves the camera towards its CameraFollow target, if it has one.
+func (c *CameraSystem) follow(em *ecs.EntityManager, camera ecs.EntityID) {
+	follow, ok := ecs.GetComponent[components.CameraFollow](em, camera)
+	if !ok {
+		return
+	}
+
+	target, ok := c.followTarget(em, follow)
+	if !ok {
+		return
+	}
+
+	transform := ecs.MustGetComponent[components.Transform](em, camera)
+
+	goal := deadZoneGoal(transform.Position, target, follow.DeadZone)
+	position := smooth(follow, transform.Position, goal, c.Game().DeltaTime())
+
+	bounds := follow.WorldBounds
+	if cam, ok := ecs.GetComponent[components.Camera](em, camera); ok && bounds.R > bounds.L && bounds.T > bounds.B {
+		// The view covers the pixels of its viewport, fewer world units the more the camera is zoomed in.
+		viewport, zoom := cam.ViewportRect(c.targetSize(cam)), cam.ZoomFactor()
+		position.X = clampAxis(position.X, float64(viewport.Dx())/(2*zoom), bounds.L, bounds.R)
+		position.Y = clampAxis(position.Y, float64(viewport.Dy())/(2*zoom), bounds.B, bounds.T)
+	}
+
+	transform.Position = position
+}
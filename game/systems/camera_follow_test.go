@@ -0,0 +1,126 @@
+package systems
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadZoneGoal(t *testing.T) {
+	deadZone := cp.BB{L: -10, B: -5, R: 10, T: 5}
+
+	tests := []struct {
+		name   string
+		target cp.Vector
+		want   cp.Vector
+	}{
+		{name: "inside", target: cp.Vector{X: 9, Y: -4}, want: cp.Vector{}},
+		{name: "right", target: cp.Vector{X: 15}, want: cp.Vector{X: 5}},
+		{name: "left", target: cp.Vector{X: -12}, want: cp.Vector{X: -2}},
+		{name: "above and below", target: cp.Vector{X: 3, Y: -8}, want: cp.Vector{Y: -3}},
+		{name: "corner", target: cp.Vector{X: 20, Y: 20}, want: cp.Vector{X: 10, Y: 15}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, deadZoneGoal(cp.Vector{}, tt.target, deadZone))
+		})
+	}
+}
+
+func TestSmooth(t *testing.T) {
+	goal := cp.Vector{X: 100}
+
+	// Without a speed the camera snaps to the goal.
+	follow := &components.CameraFollow{Smoothing: components.SmoothingLerp, Velocity: cp.Vector{X: 3}}
+	require.Equal(t, goal, smooth(follow, cp.Vector{}, goal, 0.1))
+	require.Zero(t, follow.Velocity)
+
+	// Lerp closes 1-e^(-speed*dt) of the distance, independent of how dt is split.
+	follow = &components.CameraFollow{Smoothing: components.SmoothingLerp, Speed: 5}
+	once := smooth(follow, cp.Vector{}, goal, 0.2)
+	require.InDelta(t, 100*(1-math.Exp(-1)), once.X, 1e-9)
+	twice := smooth(follow, smooth(follow, cp.Vector{}, goal, 0.1), goal, 0.1)
+	require.InDelta(t, once.X, twice.X, 1e-9)
+
+	// A critically damped spring eases towards the goal without overshooting it.
+	follow = &components.CameraFollow{Smoothing: components.SmoothingSpring, Speed: 10}
+	position := cp.Vector{}
+	previous := position
+	for range 300 {
+		position = smooth(follow, position, goal, 1.0/60)
+		require.GreaterOrEqual(t, position.X, previous.X)
+		require.LessOrEqual(t, position.X, goal.X)
+		previous = position
+	}
+	require.InDelta(t, 100, position.X, 0.01)
+}
+
+func TestClampAxis(t *testing.T) {
+	tests := []struct {
+		name   string
+		center float64
+		want   float64
+	}{
+		{name: "inside", center: 50, want: 50},
+		{name: "below", center: 0, want: 20},
+		{name: "above", center: 100, want: 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.InDelta(t, tt.want, clampAxis(tt.center, 20, 0, 100), 1e-9)
+		})
+	}
+
+	// A view wider than the range is centered on it.
+	require.InDelta(t, 50, clampAxis(10, 60, 0, 100), 1e-9)
+}
+
+func TestCameraSystem_Follow(t *testing.T) {
+	em := ecs.NewEntityManager()
+	systemManager := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{ScreenWidth: 800, ScreenHeight: 600}))
+	c := NewCameraSystem(0)
+	systemManager.Add(c)
+
+	player, err := em.NewEntity()
+	require.NoError(t, err)
+	_, err = ecs.AddComponent[components.Player](em, player)
+	require.NoError(t, err)
+	playerTransform, err := ecs.AddComponent[components.Transform](em, player)
+	require.NoError(t, err)
+	playerTransform.SetPosition(10, 0)
+	rigidBody, err := ecs.AddComponent[components.RigidBody](em, player)
+	require.NoError(t, err)
+	rigidBody.Velocity = cp.Vector{X: 100}
+
+	camera, err := em.NewEntity()
+	require.NoError(t, err)
+	cameraComponent, err := ecs.AddComponent[components.Camera](em, camera)
+	require.NoError(t, err)
+	// Half the screen at twice the zoom shows 200x150 world units.
+	cameraComponent.Viewport = components.Viewport{Width: 0.5, Height: 0.5}
+	cameraComponent.Zoom = 2
+	cameraTransform, err := ecs.AddComponent[components.Transform](em, camera)
+	require.NoError(t, err)
+	follow, err := ecs.AddComponent[components.CameraFollow](em, camera)
+	require.NoError(t, err)
+	follow.LookAhead = 0.5
+
+	// The camera looks ahead of the player by half a second of its velocity.
+	target, ok := c.followTarget(em, follow)
+	require.True(t, ok)
+	require.Equal(t, cp.Vector{X: 60}, target)
+
+	c.follow(em, camera)
+	require.Equal(t, cp.Vector{X: 60}, cameraTransform.Position)
+
+	// The view is kept inside the world by half its size in world units.
+	follow.WorldBounds = cp.BB{L: -500, B: -500, R: 150, T: 500}
+	c.follow(em, camera)
+	require.Equal(t, cp.Vector{X: 50}, cameraTransform.Position)
+}
@@ -2,6 +2,7 @@ package systems
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -37,12 +38,16 @@ func (g *GameOverSystem) Update() error {
 
 	em := g.EntityManager()
 
-	// Check if player has collision with an obstacle
-	for _, entity := range ecs.Query2[components.Player, components.Collision](em) {
-		collision := ecs.MustGetComponent[components.Collision](em, entity)
+	// Check if player has started touching an obstacle
+	for _, entity := range ecs.Query2[components.Player, components.CollisionEvents](em) {
+		events := ecs.MustGetComponent[components.CollisionEvents](em, entity)
 
-		// Check if the collision is with an obstacle
-		if ecs.HasComponent[components.Obstacle](em, collision.Entity) {
+		// Check if any new contact is with an obstacle
+		hitObstacle := slices.ContainsFunc(events.Enter, func(contact components.Contact) bool {
+			return ecs.HasComponent[components.Obstacle](em, contact.Entity)
+		})
+
+		if hitObstacle {
 			// Game over!
 			g.gameOver = true
 
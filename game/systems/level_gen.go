@@ -2,13 +2,16 @@ package systems
 
 import (
 	"fmt"
+	"image"
 	"image/color"
+	"image/draw"
 	"math/rand/v2"
 	"slices"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/jakecoffman/cp"
 	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/assets"
 	"github.com/samix73/game/game/components"
 	"github.com/samix73/game/helpers"
 )
@@ -30,6 +33,8 @@ var _ ecs.System = (*LevelGenSystem)(nil)
 type LevelGenSystem struct {
 	*ecs.BaseSystem
 	lastSpawnX float64
+	// pipeSprite is shared by every pipe, it lives in the sprite atlas.
+	pipeSprite *ebiten.Image
 }
 
 func NewLevelGenSystem(priority int) *LevelGenSystem {
@@ -59,6 +64,7 @@ func (l *LevelGenSystem) createPipe(em *ecs.EntityManager, position cp.Vector) e
 		return fmt.Errorf("error adding collider: %w", err)
 	}
 	collider.SetSize(pipeWidth, pipeHeight)
+	collider.Category = components.LayerObstacle
 
 	// Add Obstacle tag
 	ecs.AddComponent[components.Obstacle](em, entityID)
@@ -69,10 +75,7 @@ func (l *LevelGenSystem) createPipe(em *ecs.EntityManager, position cp.Vector) e
 		return fmt.Errorf("error adding renderable: %w", err)
 	}
 
-	// Create a simple green rectangle sprite for the pipe
-	pipeImage := ebiten.NewImage(int(pipeWidth), int(pipeHeight))
-	pipeImage.Fill(color.RGBA{34, 139, 34, 255}) // Green color
-	renderable.Sprite = pipeImage
+	renderable.Sprite = l.pipeSprite
 	renderable.Order = 1 // Render behind player
 
 	return nil
@@ -168,6 +171,11 @@ func (l *LevelGenSystem) Update() error {
 }
 
 func (l *LevelGenSystem) Start() error {
+	// A simple green rectangle sprite for the pipes
+	pipeImage := image.NewRGBA(image.Rect(0, 0, int(pipeWidth), int(pipeHeight)))
+	draw.Draw(pipeImage, pipeImage.Bounds(), image.NewUniform(color.RGBA{34, 139, 34, 255}), image.Point{}, draw.Src)
+	l.pipeSprite = assets.AddSprite("pipe", pipeImage)
+
 	return nil
 }
 
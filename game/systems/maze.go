@@ -0,0 +1,260 @@
+package systems
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/samix73/game/game/entities"
+)
+
+const (
+	defaultMazeCellSize = 32.0
+	mazeTileLayer       = 0
+
+	mazeFloorTile = 0
+	mazeWallTile  = 1
+)
+
+var (
+	mazeFloorColor = color.RGBA{40, 40, 48, 255}
+	mazeWallColor  = color.RGBA{110, 110, 130, 255}
+)
+
+var _ ecs.System = (*MazeSystem)(nil)
+
+func init() {
+	ecs.RegisterSystem(NewMazeSystem)
+}
+
+// mazeRect is a rectangle of wall cells in maze grid coordinates.
+type mazeRect struct {
+	x, y int
+	w, h int
+}
+
+// MazeSystem turns Maze components into static wall colliders and a tilemap on Start,
+// and places the players at the entry of the maze.
+type MazeSystem struct {
+	*ecs.BaseSystem
+}
+
+func NewMazeSystem(priority int) *MazeSystem {
+	return &MazeSystem{
+		BaseSystem: ecs.NewBaseSystem(priority),
+	}
+}
+
+func (m *MazeSystem) validateMaze(maze *components.Maze) error {
+	if maze.Width <= 0 || maze.Height <= 0 {
+		return fmt.Errorf("invalid maze size %dx%d", maze.Width, maze.Height)
+	}
+
+	if len(maze.Cells) != maze.Height {
+		return fmt.Errorf("maze has %d rows, expected %d", len(maze.Cells), maze.Height)
+	}
+
+	for y, row := range maze.Cells {
+		if len(row) != maze.Width {
+			return fmt.Errorf("maze row %d has %d cells, expected %d", y, len(row), maze.Width)
+		}
+	}
+
+	return nil
+}
+
+// mergeWalls greedily merges adjacent wall cells into as few rectangles as possible.
+// Each rectangle is grown to the right first and then downwards while the whole row stays a wall.
+func (m *MazeSystem) mergeWalls(maze *components.Maze) []mazeRect {
+	used := make([][]bool, maze.Height)
+	for y := range used {
+		used[y] = make([]bool, maze.Width)
+	}
+
+	free := func(x, y int) bool {
+		return maze.IsWall(x, y) && !used[y][x]
+	}
+
+	rects := make([]mazeRect, 0)
+
+	for y := 0; y < maze.Height; y++ {
+		for x := 0; x < maze.Width; x++ {
+			if !free(x, y) {
+				continue
+			}
+
+			w := 1
+			for x+w < maze.Width && free(x+w, y) {
+				w++
+			}
+
+			h := 1
+		grow:
+			for y+h < maze.Height {
+				for i := x; i < x+w; i++ {
+					if !free(i, y+h) {
+						break grow
+					}
+				}
+				h++
+			}
+
+			for j := y; j < y+h; j++ {
+				for i := x; i < x+w; i++ {
+					used[j][i] = true
+				}
+			}
+
+			rects = append(rects, mazeRect{x: x, y: y, w: w, h: h})
+		}
+	}
+
+	return rects
+}
+
+// boundaryWalls returns the walls just outside the grid, which keep players inside
+// when a maze has openings in its outer wall or no outer wall at all.
+func (m *MazeSystem) boundaryWalls(maze *components.Maze) []mazeRect {
+	return []mazeRect{
+		{x: -1, y: -1, w: maze.Width + 2, h: 1},
+		{x: -1, y: maze.Height, w: maze.Width + 2, h: 1},
+		{x: -1, y: 0, w: 1, h: maze.Height},
+		{x: maze.Width, y: 0, w: 1, h: maze.Height},
+	}
+}
+
+// cellToWorld converts maze grid coordinates to world coordinates.
+// The maze is centered on origin, with row 0 at the top.
+func (m *MazeSystem) cellToWorld(maze *components.Maze, origin cp.Vector, cellSize, x, y float64) cp.Vector {
+	return cp.Vector{
+		X: origin.X - float64(maze.Width)*cellSize/2 + x*cellSize,
+		Y: origin.Y + float64(maze.Height)*cellSize/2 - y*cellSize,
+	}
+}
+
+func (m *MazeSystem) createWall(em *ecs.EntityManager, position cp.Vector, width, height float64) error {
+	entityID, err := em.NewEntity()
+	if err != nil {
+		return fmt.Errorf("error creating entity: %w", err)
+	}
+
+	transform, err := ecs.AddComponent[components.Transform](em, entityID)
+	if err != nil {
+		return fmt.Errorf("error adding transform: %w", err)
+	}
+	transform.SetPosition(position.X, position.Y)
+
+	collider, err := ecs.AddComponent[components.Collider](em, entityID)
+	if err != nil {
+		return fmt.Errorf("error adding collider: %w", err)
+	}
+	collider.SetSize(width, height)
+	collider.Category = components.LayerWall
+
+	return nil
+}
+
+func (m *MazeSystem) buildTileAtlas(tileSize int) *ebiten.Image {
+	atlas := ebiten.NewImage(tileSize*2, tileSize)
+
+	atlas.SubImage(image.Rect(mazeFloorTile*tileSize, 0, (mazeFloorTile+1)*tileSize, tileSize)).(*ebiten.Image).Fill(mazeFloorColor)
+	atlas.SubImage(image.Rect(mazeWallTile*tileSize, 0, (mazeWallTile+1)*tileSize, tileSize)).(*ebiten.Image).Fill(mazeWallColor)
+
+	return atlas
+}
+
+func (m *MazeSystem) createTileMap(em *ecs.EntityManager, maze *components.Maze, origin cp.Vector, cellSize float64) error {
+	tileSize := max(int(math.Round(cellSize)), 1)
+
+	tiles := make([]int, maze.Width*maze.Height)
+	for y := 0; y < maze.Height; y++ {
+		for x := 0; x < maze.Width; x++ {
+			tile := mazeFloorTile
+			if maze.IsWall(x, y) {
+				tile = mazeWallTile
+			}
+
+			tiles[y*maze.Width+x] = tile
+		}
+	}
+
+	entityID, err := entities.NewTileMapEntity(em, m.buildTileAtlas(tileSize), mazeTileLayer, tileSize, maze.Width, maze.Height, tiles)
+	if err != nil {
+		return fmt.Errorf("error creating tilemap: %w", err)
+	}
+
+	transform := ecs.MustGetComponent[components.Transform](em, entityID)
+	transform.SetPosition(origin.X, origin.Y)
+
+	return nil
+}
+
+func (m *MazeSystem) buildMaze(em *ecs.EntityManager, entity ecs.EntityID, maze *components.Maze) error {
+	if err := m.validateMaze(maze); err != nil {
+		return err
+	}
+
+	cellSize := maze.CellSize
+	if cellSize <= 0 {
+		cellSize = defaultMazeCellSize
+	}
+
+	var origin cp.Vector
+	if transform, ok := ecs.GetComponent[components.Transform](em, entity); ok {
+		origin = transform.Position
+	}
+
+	for _, rect := range append(m.mergeWalls(maze), m.boundaryWalls(maze)...) {
+		center := m.cellToWorld(maze, origin, cellSize,
+			float64(rect.x)+float64(rect.w)/2,
+			float64(rect.y)+float64(rect.h)/2,
+		)
+
+		if err := m.createWall(em, center, float64(rect.w)*cellSize, float64(rect.h)*cellSize); err != nil {
+			return fmt.Errorf("error creating wall: %w", err)
+		}
+	}
+
+	if err := m.createTileMap(em, maze, origin, cellSize); err != nil {
+		return err
+	}
+
+	m.placePlayers(em, maze, origin, cellSize)
+
+	return nil
+}
+
+// placePlayers moves every Player to the center of the maze entry.
+func (m *MazeSystem) placePlayers(em *ecs.EntityManager, maze *components.Maze, origin cp.Vector, cellSize float64) {
+	entry := m.cellToWorld(maze, origin, cellSize, math.Floor(maze.Entry.X)+0.5, math.Floor(maze.Entry.Y)+0.5)
+
+	for _, entity := range ecs.Query2[components.Player, components.Transform](em) {
+		ecs.MustGetComponent[components.Transform](em, entity).SetPosition(entry.X, entry.Y)
+	}
+}
+
+func (m *MazeSystem) Update() error {
+	return nil
+}
+
+func (m *MazeSystem) Start() error {
+	em := m.EntityManager()
+
+	for _, entity := range ecs.Query[components.Maze](em) {
+		maze := ecs.MustGetComponent[components.Maze](em, entity)
+
+		if err := m.buildMaze(em, entity, maze); err != nil {
+			return fmt.Errorf("systems.MazeSystem.Start error building maze %d: %w", entity, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *MazeSystem) Teardown() {
+}
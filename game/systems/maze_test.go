@@ -0,0 +1,155 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/stretchr/testify/require"
+)
+
+// testMaze builds a maze from rows of '#' walls and '.' floors.
+func testMaze(rows ...string) *components.Maze {
+	maze := &components.Maze{Width: len(rows[0]), Height: len(rows)}
+	for _, row := range rows {
+		cells := make([]uint8, len(row))
+		for x, c := range row {
+			if c == '.' {
+				cells[x] = components.MazeFloor
+			}
+		}
+
+		maze.Cells = append(maze.Cells, cells)
+	}
+
+	return maze
+}
+
+func TestMazeSystem_MergeWalls(t *testing.T) {
+	generated, err := components.GenerateMaze(21, 15, cp.Vector{X: 1, Y: 0}, cp.Vector{X: 19, Y: 14}, 7, 32, components.MazeOptions{Braid: 0.3})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		maze  *components.Maze
+		rects int
+	}{
+		{name: "floor", maze: testMaze("...", "..."), rects: 0},
+		{name: "solid", maze: testMaze("###", "###"), rects: 1},
+		{name: "ring", maze: testMaze("####", "#..#", "####"), rects: 4},
+		{name: "cross", maze: testMaze(".#.", "###", ".#."), rects: 3},
+		{name: "generated", maze: &generated, rects: -1},
+	}
+
+	m := NewMazeSystem(0)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rects := m.mergeWalls(tt.maze)
+			if tt.rects >= 0 {
+				require.Len(t, rects, tt.rects)
+			}
+
+			// Every wall cell is covered exactly once and no floor cell is covered.
+			covered := make(map[[2]int]int)
+			for _, rect := range rects {
+				require.Positive(t, rect.w)
+				require.Positive(t, rect.h)
+
+				for y := rect.y; y < rect.y+rect.h; y++ {
+					for x := rect.x; x < rect.x+rect.w; x++ {
+						require.True(t, tt.maze.IsWall(x, y), "floor cell %d,%d is covered", x, y)
+						covered[[2]int{x, y}]++
+					}
+				}
+			}
+
+			for y := range tt.maze.Height {
+				for x := range tt.maze.Width {
+					want := 0
+					if tt.maze.IsWall(x, y) {
+						want = 1
+					}
+
+					require.Equal(t, want, covered[[2]int{x, y}], "cell %d,%d", x, y)
+				}
+			}
+		})
+	}
+}
+
+func TestMazeSystem_CellToWorld(t *testing.T) {
+	maze := testMaze("....", "....")
+	m := NewMazeSystem(0)
+
+	tests := []struct {
+		name   string
+		origin cp.Vector
+		x, y   float64
+		want   cp.Vector
+	}{
+		{name: "top left", x: 0, y: 0, want: cp.Vector{X: -20, Y: 10}},
+		{name: "bottom right", x: 4, y: 2, want: cp.Vector{X: 20, Y: -10}},
+		{name: "center", x: 2, y: 1, want: cp.Vector{}},
+		{name: "offset origin", origin: cp.Vector{X: 100, Y: -50}, x: 2, y: 1, want: cp.Vector{X: 100, Y: -50}},
+		{name: "cell center", x: 0.5, y: 0.5, want: cp.Vector{X: -15, Y: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, m.cellToWorld(maze, tt.origin, 10, tt.x, tt.y))
+		})
+	}
+}
+
+func TestMazeSystem_BuildMaze(t *testing.T) {
+	em := ecs.NewEntityManager()
+	m := NewMazeSystem(0)
+
+	mazeEntity, err := em.NewEntity()
+	require.NoError(t, err)
+	maze, err := ecs.AddComponent[components.Maze](em, mazeEntity)
+	require.NoError(t, err)
+	*maze = *testMaze(
+		"#.###",
+		"#...#",
+		"###.#",
+	)
+	maze.CellSize = 10
+	maze.Entry = cp.Vector{X: 1, Y: 0}
+
+	player, err := em.NewEntity()
+	require.NoError(t, err)
+	_, err = ecs.AddComponent[components.Player](em, player)
+	require.NoError(t, err)
+	_, err = ecs.AddComponent[components.Transform](em, player)
+	require.NoError(t, err)
+
+	require.NoError(t, m.buildMaze(em, mazeEntity, maze))
+
+	var area float64
+	var extent cp.BB
+	walls := 0
+	for _, entity := range ecs.Query2[components.Collider, components.Transform](em) {
+		collider := ecs.MustGetComponent[components.Collider](em, entity)
+		require.Equal(t, components.LayerWall, collider.Category)
+
+		bounds := collider.LocalBounds().Offset(ecs.MustGetComponent[components.Transform](em, entity).Position)
+		area += bounds.Area()
+		if walls == 0 {
+			extent = bounds
+		} else {
+			extent = extent.Merge(bounds)
+		}
+		walls++
+	}
+
+	// 10 wall cells of 10x10 and 20 boundary cells around them, centered on the origin.
+	require.InDelta(t, 3000, area, 1e-9)
+	require.Equal(t, cp.BB{L: -35, B: -25, R: 35, T: 25}, extent)
+	require.Len(t, ecs.Query[components.TileMap](em), 1)
+
+	// The player starts in the middle of the entry cell.
+	require.Equal(t, cp.Vector{X: -10, Y: 10}, ecs.MustGetComponent[components.Transform](em, player).Position)
+}
@@ -0,0 +1,224 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+const broadPhaseCellSize = 128.0
+
+type gridCell struct {
+	x, y int
+}
+
+type gridEntry struct {
+	bounds cp.BB
+	// minCell and maxCell are the inclusive range of cells the entry is stored in.
+	minCell, maxCell gridCell
+}
+
+// spatialGrid is a uniform grid broad phase.
+// Every entity is stored in each cell its bounds overlap, so queries only
+// have to look at the cells overlapped by the query bounds.
+type spatialGrid struct {
+	cellSize float64
+	cells    map[gridCell][]ecs.EntityID
+	entries  map[ecs.EntityID]gridEntry
+
+	// extent contains the bounds of every entity inserted since the grid was last empty.
+	// It only grows, queries are clamped to it so infinite bounds have a cell range.
+	extent cp.BB
+}
+
+func newSpatialGrid(cellSize float64) *spatialGrid {
+	return &spatialGrid{
+		cellSize: cellSize,
+		cells:    make(map[gridCell][]ecs.EntityID),
+		entries:  make(map[ecs.EntityID]gridEntry),
+	}
+}
+
+func (g *spatialGrid) cellAt(x, y float64) gridCell {
+	return gridCell{
+		x: int(math.Floor(x / g.cellSize)),
+		y: int(math.Floor(y / g.cellSize)),
+	}
+}
+
+func (g *spatialGrid) cellRange(bounds cp.BB) (gridCell, gridCell) {
+	return g.cellAt(bounds.L, bounds.B), g.cellAt(bounds.R, bounds.T)
+}
+
+// cellBounds returns the world area covered by a cell.
+func (g *spatialGrid) cellBounds(cell gridCell) cp.BB {
+	return cp.BB{
+		L: float64(cell.x) * g.cellSize, B: float64(cell.y) * g.cellSize,
+		R: float64(cell.x+1) * g.cellSize, T: float64(cell.y+1) * g.cellSize,
+	}
+}
+
+func (g *spatialGrid) Len() int {
+	return len(g.entries)
+}
+
+func (g *spatialGrid) Contains(entity ecs.EntityID) bool {
+	_, ok := g.entries[entity]
+	return ok
+}
+
+// Bounds returns the bounds an entity was last inserted or updated with.
+func (g *spatialGrid) Bounds(entity ecs.EntityID) (cp.BB, bool) {
+	entry, ok := g.entries[entity]
+	return entry.bounds, ok
+}
+
+func (g *spatialGrid) Insert(entity ecs.EntityID, bounds cp.BB) {
+	if len(g.entries) == 0 {
+		g.extent = bounds
+	} else {
+		g.extent = g.extent.Merge(bounds)
+	}
+
+	minCell, maxCell := g.cellRange(bounds)
+
+	for y := minCell.y; y <= maxCell.y; y++ {
+		for x := minCell.x; x <= maxCell.x; x++ {
+			cell := gridCell{x: x, y: y}
+			g.cells[cell] = append(g.cells[cell], entity)
+		}
+	}
+
+	g.entries[entity] = gridEntry{
+		bounds:  bounds,
+		minCell: minCell,
+		maxCell: maxCell,
+	}
+}
+
+func (g *spatialGrid) Remove(entity ecs.EntityID) {
+	entry, ok := g.entries[entity]
+	if !ok {
+		return
+	}
+
+	for y := entry.minCell.y; y <= entry.maxCell.y; y++ {
+		for x := entry.minCell.x; x <= entry.maxCell.x; x++ {
+			cell := gridCell{x: x, y: y}
+
+			entities := g.cells[cell]
+			for i, e := range entities {
+				if e == entity {
+					entities[i] = entities[len(entities)-1]
+					entities = entities[:len(entities)-1]
+					break
+				}
+			}
+
+			if len(entities) == 0 {
+				delete(g.cells, cell)
+			} else {
+				g.cells[cell] = entities
+			}
+		}
+	}
+
+	delete(g.entries, entity)
+}
+
+// Update moves an entity to new bounds, only touching the cells when it crossed a cell boundary.
+func (g *spatialGrid) Update(entity ecs.EntityID, bounds cp.BB) {
+	entry, ok := g.entries[entity]
+	if !ok {
+		g.Insert(entity, bounds)
+		return
+	}
+
+	minCell, maxCell := g.cellRange(bounds)
+	if minCell == entry.minCell && maxCell == entry.maxCell {
+		entry.bounds = bounds
+		g.entries[entity] = entry
+		g.extent = g.extent.Merge(bounds)
+		return
+	}
+
+	g.Remove(entity)
+	g.Insert(entity, bounds)
+}
+
+// Query calls fn once for every entity whose bounds intersect the given bounds.
+// fn must not modify the grid, but may run queries of its own.
+func (g *spatialGrid) Query(bounds cp.BB, fn func(entity ecs.EntityID, bounds cp.BB)) {
+	if len(g.entries) == 0 {
+		return
+	}
+
+	clamped := cp.BB{
+		L: max(bounds.L, g.extent.L),
+		B: max(bounds.B, g.extent.B),
+		R: min(bounds.R, g.extent.R),
+		T: min(bounds.T, g.extent.T),
+	}
+	// Also rejects NaN bounds, which would convert to arbitrary cells.
+	if !(clamped.L <= clamped.R && clamped.B <= clamped.T) {
+		return
+	}
+
+	minCell, maxCell := g.cellRange(clamped)
+
+	visit := func(cell gridCell, entities []ecs.EntityID) {
+		for _, entity := range entities {
+			entry := g.entries[entity]
+
+			// An entity stored in more than one cell is only reported from the
+			// first cell it shares with the query.
+			if cell.x != max(entry.minCell.x, minCell.x) || cell.y != max(entry.minCell.y, minCell.y) {
+				continue
+			}
+
+			if !entry.bounds.Intersects(bounds) {
+				continue
+			}
+
+			fn(entity, entry.bounds)
+		}
+	}
+
+	// A range with more cells than are occupied, such as one spanning two entities far
+	// apart, is cheaper to check cell by cell than to walk.
+	if float64(maxCell.x-minCell.x+1)*float64(maxCell.y-minCell.y+1) > float64(len(g.cells)) {
+		for cell, entities := range g.cells {
+			if cell.x >= minCell.x && cell.x <= maxCell.x && cell.y >= minCell.y && cell.y <= maxCell.y {
+				visit(cell, entities)
+			}
+		}
+
+		return
+	}
+
+	for y := minCell.y; y <= maxCell.y; y++ {
+		for x := minCell.x; x <= maxCell.x; x++ {
+			cell := gridCell{x: x, y: y}
+			visit(cell, g.cells[cell])
+		}
+	}
+}
+
+// gridSet indexes colliders in several grids, such as the static and the dynamic grid of
+// CollisionSystem. A collider is in at most one of them.
+type gridSet []*spatialGrid
+
+func (s gridSet) Query(bounds cp.BB, fn func(entity ecs.EntityID, bounds cp.BB)) {
+	for _, grid := range s {
+		grid.Query(bounds, fn)
+	}
+}
+
+func (s gridSet) Cells(fn func(bounds cp.BB)) {
+	for _, grid := range s {
+		for cell := range grid.cells {
+			fn(grid.cellBounds(cell))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package physics
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/require"
+)
+
+func queryGrid(g *spatialGrid, bounds cp.BB) []ecs.EntityID {
+	found := make([]ecs.EntityID, 0)
+	g.Query(bounds, func(entity ecs.EntityID, _ cp.BB) {
+		found = append(found, entity)
+	})
+	slices.Sort(found)
+
+	return found
+}
+
+func TestSpatialGrid_Query(t *testing.T) {
+	g := newSpatialGrid(10)
+
+	g.Insert(1, cp.BB{L: 0, B: 0, R: 5, T: 5})
+	g.Insert(2, cp.BB{L: -25, B: -25, R: 25, T: 25}) // spans many cells
+	g.Insert(3, cp.BB{L: 100, B: 100, R: 105, T: 105})
+
+	require.Equal(t, 3, g.Len())
+	require.Equal(t, []ecs.EntityID{1, 2}, queryGrid(g, cp.BB{L: 1, B: 1, R: 2, T: 2}))
+	require.Equal(t, []ecs.EntityID{2}, queryGrid(g, cp.BB{L: -20, B: -20, R: -15, T: -15}))
+	require.Equal(t, []ecs.EntityID{3}, queryGrid(g, cp.BB{L: 99, B: 99, R: 101, T: 101}))
+	require.Empty(t, queryGrid(g, cp.BB{L: 50, B: 50, R: 60, T: 60}))
+}
+
+func TestSpatialGrid_CellBounds(t *testing.T) {
+	g := newSpatialGrid(10)
+	require.Equal(t, cp.BB{L: -10, B: 20, R: 0, T: 30}, g.cellBounds(gridCell{x: -1, y: 2}))
+}
+
+func TestSpatialGrid_UpdateAndRemove(t *testing.T) {
+	g := newSpatialGrid(10)
+
+	g.Insert(1, cp.BB{L: 0, B: 0, R: 5, T: 5})
+
+	// Moving within the same cell keeps the entity queryable at its new bounds.
+	g.Update(1, cp.BB{L: 1, B: 1, R: 6, T: 6})
+	require.Equal(t, []ecs.EntityID{1}, queryGrid(g, cp.BB{L: 5.5, B: 5.5, R: 5.8, T: 5.8}))
+
+	// Moving across cells leaves nothing behind in the old cells.
+	g.Update(1, cp.BB{L: 50, B: 50, R: 55, T: 55})
+	require.Empty(t, queryGrid(g, cp.BB{L: 0, B: 0, R: 9, T: 9}))
+	require.Equal(t, []ecs.EntityID{1}, queryGrid(g, cp.BB{L: 51, B: 51, R: 52, T: 52}))
+
+	g.Remove(1)
+	require.Zero(t, g.Len())
+	require.False(t, g.Contains(1))
+	require.Empty(t, g.cells)
+}
+
+func TestSpatialGrid_NestedQuery(t *testing.T) {
+	g := newSpatialGrid(10)
+
+	g.Insert(1, cp.BB{L: 0, B: 0, R: 25, T: 25})
+	g.Insert(2, cp.BB{L: 5, B: 5, R: 15, T: 15})
+
+	pairs := make(map[[2]ecs.EntityID]int)
+	g.Query(cp.BB{L: 0, B: 0, R: 30, T: 30}, func(a ecs.EntityID, bounds cp.BB) {
+		g.Query(bounds, func(b ecs.EntityID, _ cp.BB) {
+			pairs[[2]ecs.EntityID{a, b}]++
+		})
+	})
+
+	// Every pair is reported once, the inner queries don't hide entities from the outer one.
+	require.Equal(t, map[[2]ecs.EntityID]int{{1, 1}: 1, {1, 2}: 1, {2, 1}: 1, {2, 2}: 1}, pairs)
+}
+
+func TestSpatialGrid_UnboundedQuery(t *testing.T) {
+	g := newSpatialGrid(10)
+
+	require.Empty(t, queryGrid(g, cp.NewBBForExtents(cp.Vector{}, math.Inf(1), math.Inf(1))))
+
+	g.Insert(1, cp.BB{L: 0, B: 0, R: 5, T: 5})
+	g.Insert(2, cp.BB{L: -1e6, B: -1e6, R: -1e6 + 5, T: -1e6 + 5})
+
+	inf := math.Inf(1)
+	require.Equal(t, []ecs.EntityID{1, 2}, queryGrid(g, cp.BB{L: -inf, B: -inf, R: inf, T: inf}))
+	require.Equal(t, []ecs.EntityID{1}, queryGrid(g, cp.BB{L: -1, B: -1, R: inf, T: inf}))
+	require.Empty(t, queryGrid(g, cp.BB{L: math.NaN(), B: 0, R: 1, T: 1}))
+}
@@ -1,7 +1,11 @@
 package physics
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"math"
+	"slices"
 
 	"github.com/jakecoffman/cp"
 	ecs "github.com/samix73/ebiten-ecs"
@@ -14,114 +18,435 @@ func init() {
 	ecs.RegisterSystem(NewCollisionSystem)
 }
 
+// ccdSkin is how far a bullet is placed into the collider it hit,
+// so the contact is reported and resolved like any other overlap.
+const ccdSkin = 0.01
+
 type collisionCandidate struct {
-	id     ecs.EntityID
-	bounds cp.BB
+	id       ecs.EntityID
+	bounds   cp.BB
+	collider *components.Collider
+
+	// previous are the bounds of the last update, used to sweep bullets.
+	previous    cp.BB
+	hasPrevious bool
+	bullet      bool
 }
 
+// collisionPair is an unordered pair of colliding entities, stored with a < b.
+// The manifold normal points from a towards b.
+type collisionPair struct {
+	a, b ecs.EntityID
+	manifold
+	trigger bool
+}
+
+func newCollisionPair(a, b ecs.EntityID, m manifold, trigger bool) collisionPair {
+	if a > b {
+		a, b = b, a
+		m.normal = m.normal.Neg()
+	}
+
+	return collisionPair{a: a, b: b, manifold: m, trigger: trigger}
+}
+
+// CollisionSystem detects overlapping colliders and reports them as Collision contacts
+// and CollisionEvents.
+// Colliders with a dynamic or kinematic RigidBody are updated incrementally in a dynamic
+// broad phase grid every frame. All other colliders are kept in a static grid whose cells
+// are only touched when a collider is added, removed or moved by its Transform.
 type CollisionSystem struct {
 	*ecs.BaseSystem
+
+	static  *spatialGrid
+	dynamic *spatialGrid
+
+	// frame stamps the colliders seen by the current update, colliders with an older
+	// stamp no longer exist or moved to the other grid.
+	frame       uint64
+	dynamicSeen map[ecs.EntityID]uint64
+	staticSeen  map[ecs.EntityID]uint64
+
+	// excluded are the pairs linked by a Joint that don't collide, refreshed every update.
+	excluded map[entityPair]struct{}
+
+	// broadPhase is the entity of the BroadPhase singleton, 0 before Start.
+	broadPhase ecs.EntityID
 }
 
 func NewCollisionSystem(priority int) *CollisionSystem {
 	return &CollisionSystem{
-		BaseSystem: ecs.NewBaseSystem(priority),
+		BaseSystem:  ecs.NewBaseSystem(priority),
+		static:      newSpatialGrid(broadPhaseCellSize),
+		dynamic:     newSpatialGrid(broadPhaseCellSize),
+		dynamicSeen: make(map[ecs.EntityID]uint64),
+		staticSeen:  make(map[ecs.EntityID]uint64),
 	}
 }
 
-func (c *CollisionSystem) checkCollision(a, b collisionCandidate) bool {
-	return a.bounds.Intersects(b.bounds)
+// contactEvents collects the events of a single entity while contacts are written.
+type contactEvents struct {
+	enter, stay, exit []components.Contact
 }
 
-func (c *CollisionSystem) registerCollision(a, b ecs.EntityID) error {
-	aCol, err := ecs.AddComponent[components.Collision](c.EntityManager(), a)
-	if err != nil {
-		return fmt.Errorf("error adding collision: %w", err)
+// writeContacts stores every pair as a contact on both of its entities and removes
+// the Collision component from entities that no longer overlap anything.
+// Contacts are compared with the previous frame to emit enter, stay and exit events.
+func (c *CollisionSystem) writeContacts(em *ecs.EntityManager, pairs []collisionPair) error {
+	contacts := make(map[ecs.EntityID][]components.Contact, len(pairs)*2)
+	for _, pair := range pairs {
+		contacts[pair.a] = append(contacts[pair.a], components.Contact{
+			Entity:      pair.b,
+			Penetration: pair.penetration,
+			Normal:      pair.normal,
+			Trigger:     pair.trigger,
+		})
+		contacts[pair.b] = append(contacts[pair.b], components.Contact{
+			Entity:      pair.a,
+			Penetration: pair.penetration,
+			Normal:      pair.normal.Neg(),
+			Trigger:     pair.trigger,
+		})
 	}
-	aCol.Entity = b
 
-	bCol, err := ecs.AddComponent[components.Collision](c.EntityManager(), b)
-	if err != nil {
-		return fmt.Errorf("error adding collision: %w", err)
+	events := make(map[ecs.EntityID]*contactEvents)
+	eventsOf := func(entity ecs.EntityID) *contactEvents {
+		e, ok := events[entity]
+		if !ok {
+			e = &contactEvents{}
+			events[entity] = e
+		}
+
+		return e
+	}
+
+	for _, entity := range ecs.Query[components.Collision](em) {
+		collision := ecs.MustGetComponent[components.Collision](em, entity)
+		current := contacts[entity]
+
+		for _, previous := range collision.Contacts {
+			ended := !slices.ContainsFunc(current, func(contact components.Contact) bool {
+				return contact.Entity == previous.Entity
+			})
+			if ended {
+				eventsOf(entity).exit = append(eventsOf(entity).exit, previous)
+			}
+		}
+
+		if len(current) > 0 {
+			continue
+		}
+
+		if err := ecs.RemoveComponent[components.Collision](em, entity); err != nil {
+			return fmt.Errorf("error removing collision: %w", err)
+		}
+	}
+
+	for entity, entityContacts := range contacts {
+		collision, ok := ecs.GetComponent[components.Collision](em, entity)
+		if !ok {
+			var err error
+			collision, err = ecs.AddComponent[components.Collision](em, entity)
+			if err != nil {
+				return fmt.Errorf("error adding collision: %w", err)
+			}
+		}
+
+		e := eventsOf(entity)
+		for _, contact := range entityContacts {
+			if _, ok := collision.With(contact.Entity); ok {
+				e.stay = append(e.stay, contact)
+			} else {
+				e.enter = append(e.enter, contact)
+			}
+		}
+
+		collision.Contacts = append(collision.Contacts[:0], entityContacts...)
+	}
+
+	if err := c.writeEvents(em, events); err != nil {
+		return fmt.Errorf("error writing collision events: %w", err)
+	}
+
+	return nil
+}
+
+// clearEvents removes the CollisionEvents of the last frame.
+func (c *CollisionSystem) clearEvents(em *ecs.EntityManager) error {
+	for _, entity := range ecs.Query[components.CollisionEvents](em) {
+		if err := ecs.RemoveComponent[components.CollisionEvents](em, entity); err != nil {
+			return fmt.Errorf("error removing collision events: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeEvents adds the events of a detection to the CollisionEvents of this frame, so the
+// events of every step FixedStepSystem runs in a frame are kept. A contact that stays over
+// several steps is listed in Stay once, with its latest state.
+func (c *CollisionSystem) writeEvents(em *ecs.EntityManager, events map[ecs.EntityID]*contactEvents) error {
+	for entity, e := range events {
+		collisionEvents, ok := ecs.GetComponent[components.CollisionEvents](em, entity)
+		if !ok {
+			var err error
+			collisionEvents, err = ecs.AddComponent[components.CollisionEvents](em, entity)
+			if err != nil {
+				return fmt.Errorf("error adding collision events: %w", err)
+			}
+		}
+
+		collisionEvents.Enter = append(collisionEvents.Enter, e.enter...)
+		collisionEvents.Exit = append(collisionEvents.Exit, e.exit...)
+
+		for _, contact := range e.stay {
+			i := slices.IndexFunc(collisionEvents.Stay, func(stay components.Contact) bool {
+				return stay.Entity == contact.Entity
+			})
+			if i == -1 {
+				collisionEvents.Stay = append(collisionEvents.Stay, contact)
+			} else {
+				collisionEvents.Stay[i] = contact
+			}
+		}
 	}
-	bCol.Entity = a
 
 	return nil
 }
 
-func (c *CollisionSystem) removeCollision(a, b ecs.EntityID) {
-	aCol, ok := ecs.GetComponent[components.Collision](c.EntityManager(), a)
-	if ok && aCol.Entity == b {
-		ecs.RemoveComponent[components.Collision](c.EntityManager(), a)
+// collide runs the narrow phase for a candidate and another collider whose layers accept each other.
+func (c *CollisionSystem) collide(em *ecs.EntityManager, a collisionCandidate, b ecs.EntityID, bounds cp.BB) (collisionPair, bool) {
+	other := ecs.MustGetComponent[components.Collider](em, b)
+	if !a.collider.CanCollide(other) || c.isExcluded(a.id, b) {
+		return collisionPair{}, false
 	}
 
-	bCol, ok := ecs.GetComponent[components.Collision](c.EntityManager(), b)
-	if ok && bCol.Entity == a {
-		ecs.RemoveComponent[components.Collision](c.EntityManager(), b)
+	m, ok := collideAABB(a.bounds, bounds)
+	if !ok {
+		return collisionPair{}, false
 	}
+
+	return newCollisionPair(a.id, b, m, a.collider.Trigger || other.Trigger), true
 }
 
-func (c *CollisionSystem) Update() error {
-	em := c.EntityManager()
+func (c *CollisionSystem) isExcluded(a, b ecs.EntityID) bool {
+	_, ok := c.excluded[newEntityPair(a, b)]
+	return ok
+}
+
+// worldBounds returns the bounds of an entity's collider at its Transform.
+func worldBounds(em *ecs.EntityManager, entity ecs.EntityID) cp.BB {
+	transform := ecs.MustGetComponent[components.Transform](em, entity)
+	col := ecs.MustGetComponent[components.Collider](em, entity)
+
+	return col.LocalBounds().Offset(transform.Position)
+}
+
+// updateBroadPhase syncs the grids with the current colliders and returns the moving ones.
+func (c *CollisionSystem) updateBroadPhase(em *ecs.EntityManager) []collisionCandidate {
+	c.frame++
 
 	active := make([]collisionCandidate, 0, 16)
-	static := make([]collisionCandidate, 0, 1024)
 
-	for _, entity := range ecs.Query2[components.Collider, components.Transform](em) {
-		transform := ecs.MustGetComponent[components.Transform](em, entity)
-		col := ecs.MustGetComponent[components.Collider](em, entity)
+	for _, entity := range ecs.Query3[components.RigidBody, components.Collider, components.Transform](em) {
+		rigidBody := ecs.MustGetComponent[components.RigidBody](em, entity)
+		if rigidBody.Type == components.BodyStatic {
+			continue
+		}
 
-		translatedBounds := col.Bounds.Offset(transform.Position)
+		translatedBounds := worldBounds(em, entity)
+		previous, hasPrevious := c.dynamic.Bounds(entity)
 
-		if ecs.HasComponent[components.RigidBody](em, entity) {
-			active = append(active, collisionCandidate{
-				id:     entity,
-				bounds: translatedBounds,
-			})
-		} else {
-			static = append(static, collisionCandidate{
-				id:     entity,
-				bounds: translatedBounds,
-			})
+		c.static.Remove(entity)
+		c.dynamic.Update(entity, translatedBounds)
+		c.dynamicSeen[entity] = c.frame
+
+		active = append(active, collisionCandidate{
+			id:          entity,
+			bounds:      translatedBounds,
+			collider:    ecs.MustGetComponent[components.Collider](em, entity),
+			previous:    previous,
+			hasPrevious: hasPrevious,
+			bullet:      rigidBody.Bullet,
+		})
+	}
+
+	// Drop bodies that were removed or lost their RigidBody, the latter may now be static.
+	for entity, frame := range c.dynamicSeen {
+		if frame == c.frame {
+			continue
 		}
+
+		c.dynamic.Remove(entity)
+		delete(c.dynamicSeen, entity)
 	}
 
-	if len(active) == 0 {
-		return nil
+	c.updateStatic(em)
+
+	for i := range active {
+		if active[i].bullet && active[i].hasPrevious {
+			c.sweepBullet(em, &active[i])
+		}
 	}
 
-	// Active vs Static
-	for _, a := range active {
-		for _, b := range static {
-			if c.checkCollision(a, b) {
-				c.registerCollision(a.id, b.id)
-			} else {
-				c.removeCollision(a.id, b.id)
-			}
+	return active
+}
+
+// sweepBullet moves a bullet back to where it first hit a static collider since the last update,
+// so fast bodies can not tunnel through thin colliders between two updates.
+func (c *CollisionSystem) sweepBullet(em *ecs.EntityManager, a *collisionCandidate) {
+	displacement := cp.Vector{X: a.bounds.L - a.previous.L, Y: a.bounds.B - a.previous.B}
+	if displacement == (cp.Vector{}) {
+		return
+	}
+
+	toi := math.Inf(1)
+	var normal cp.Vector
+
+	c.static.Query(a.previous.Merge(a.bounds), func(b ecs.EntityID, bounds cp.BB) {
+		// Triggers don't stop bullets, they report the overlap at the end of the move.
+		other := ecs.MustGetComponent[components.Collider](em, b)
+		if !a.collider.CanCollide(other) || a.collider.Trigger || other.Trigger || c.isExcluded(a.id, b) {
+			return
+		}
+
+		if t, n, ok := sweepAABB(a.previous, displacement, bounds); ok && t < toi {
+			toi, normal = t, n
 		}
+	})
+
+	if math.IsInf(toi, 1) {
+		return
 	}
 
-	if len(active) < 2 {
-		return nil
+	correction := displacement.Mult(toi - 1).Add(normal.Mult(ccdSkin))
+	ecs.MustGetComponent[components.Transform](em, a.id).Translate(correction.X, correction.Y)
+
+	a.bounds = a.bounds.Offset(correction)
+	c.dynamic.Update(a.id, a.bounds)
+}
+
+// updateStatic inserts new static colliders, moves the ones whose Transform changed and
+// removes the ones that no longer exist. Unmoved colliders keep their cells.
+func (c *CollisionSystem) updateStatic(em *ecs.EntityManager) {
+	for _, entity := range ecs.Query2[components.Collider, components.Transform](em) {
+		if c.dynamic.Contains(entity) {
+			continue
+		}
+
+		c.staticSeen[entity] = c.frame
+
+		translatedBounds := worldBounds(em, entity)
+		if bounds, ok := c.static.Bounds(entity); !ok || bounds != translatedBounds {
+			c.static.Update(entity, translatedBounds)
+		}
 	}
 
-	// Active vs Active
-	for i := 0; i < len(active); i++ {
-		for j := i + 1; j < len(active); j++ {
-			if c.checkCollision(active[i], active[j]) {
-				c.registerCollision(active[i].id, active[j].id)
-			} else {
-				c.removeCollision(active[i].id, active[j].id)
-			}
+	for entity, frame := range c.staticSeen {
+		if frame == c.frame {
+			continue
 		}
+
+		c.static.Remove(entity)
+		delete(c.staticSeen, entity)
+	}
+}
+
+func (c *CollisionSystem) Update() error {
+	if err := c.clearEvents(c.EntityManager()); err != nil {
+		return fmt.Errorf("physics.CollisionSystem.Update: %w", err)
+	}
+
+	if err := c.detect(); err != nil {
+		return fmt.Errorf("physics.CollisionSystem.Update: %w", err)
 	}
 
 	return nil
 }
 
+// detect finds the current contacts and adds their events to this frame's CollisionEvents.
+func (c *CollisionSystem) detect() error {
+	em := c.EntityManager()
+
+	c.excluded = jointExclusions(em)
+	active := c.updateBroadPhase(em)
+
+	pairs := make([]collisionPair, 0, len(active))
+
+	for _, a := range active {
+		// Active vs Static
+		c.static.Query(a.bounds, func(b ecs.EntityID, bounds cp.BB) {
+			if pair, ok := c.collide(em, a, b, bounds); ok {
+				pairs = append(pairs, pair)
+			}
+		})
+
+		// Active vs Active, each pair is visited from its lower entity only
+		c.dynamic.Query(a.bounds, func(b ecs.EntityID, bounds cp.BB) {
+			if b <= a.id {
+				return
+			}
+
+			if pair, ok := c.collide(em, a, b, bounds); ok {
+				pairs = append(pairs, pair)
+			}
+		})
+	}
+
+	// Sort pairs so contacts are listed in the same order every frame.
+	slices.SortFunc(pairs, func(x, y collisionPair) int {
+		if x.a != y.a {
+			return cmp.Compare(x.a, y.a)
+		}
+
+		return cmp.Compare(x.b, y.b)
+	})
+
+	return c.writeContacts(em, pairs)
+}
+
+// step detects the current contacts, detection does not depend on the time step.
+// FixedStepSystem clears the events once per frame, before its first step.
+func (c *CollisionSystem) step(float64) error {
+	return c.detect()
+}
+
+// Queries returns queries over the colliders as of the last update, read from the broad phase.
+func (c *CollisionSystem) Queries() *Queries {
+	return &Queries{em: c.EntityManager(), index: gridSet{c.static, c.dynamic}}
+}
+
+// Start shares the broad phase through a BroadPhase singleton. A world runs a single
+// CollisionSystem, directly or through FixedStepSystem.
 func (c *CollisionSystem) Start() error {
+	em := c.EntityManager()
+
+	if len(ecs.Query[components.BroadPhase](em)) > 0 {
+		return errors.New("physics.CollisionSystem.Start: the world already has a CollisionSystem")
+	}
+
+	entity, err := em.NewEntity()
+	if err != nil {
+		return fmt.Errorf("physics.CollisionSystem.Start: %w", err)
+	}
+
+	broadPhase, err := ecs.AddComponent[components.BroadPhase](em, entity)
+	if err != nil {
+		return fmt.Errorf("physics.CollisionSystem.Start: %w", err)
+	}
+
+	broadPhase.Index = gridSet{c.static, c.dynamic}
+	c.broadPhase = entity
+
 	return nil
 }
 
 func (c *CollisionSystem) Teardown() {
+	if c.broadPhase == 0 {
+		return
+	}
+
+	// The entity is gone already when the world was torn down first.
+	_ = c.EntityManager().Remove(c.broadPhase)
+	c.broadPhase = 0
 }
@@ -0,0 +1,393 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/stretchr/testify/require"
+)
+
+func testCollisionSystem(tb testing.TB) (*ecs.EntityManager, *CollisionSystem) {
+	tb.Helper()
+
+	em := ecs.NewEntityManager()
+	game := ecs.NewGame(&ecs.GameConfig{})
+	systemManager := ecs.NewSystemManager(em, game)
+
+	c := NewCollisionSystem(0)
+	systemManager.Add(c)
+
+	return em, c
+}
+
+func testCollider(tb testing.TB, em *ecs.EntityManager, dynamic bool, x, y, width, height float64) ecs.EntityID {
+	tb.Helper()
+
+	entityID, err := em.NewEntity()
+	require.NoError(tb, err)
+
+	transform, err := ecs.AddComponent[components.Transform](em, entityID)
+	require.NoError(tb, err)
+	transform.SetPosition(x, y)
+
+	collider, err := ecs.AddComponent[components.Collider](em, entityID)
+	require.NoError(tb, err)
+	collider.SetSize(width, height)
+
+	if dynamic {
+		_, err := ecs.AddComponent[components.RigidBody](em, entityID)
+		require.NoError(tb, err)
+	}
+
+	return entityID
+}
+
+func TestCollisionSystem_Update(t *testing.T) {
+	em, c := testCollisionSystem(t)
+
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+	wall := testCollider(t, em, false, 8, 0, 10, 10)
+	farWall := testCollider(t, em, false, 100, 0, 10, 10)
+
+	require.NoError(t, c.Update())
+
+	collision, ok := ecs.GetComponent[components.Collision](em, body)
+	require.True(t, ok)
+	require.Len(t, collision.Contacts, 1)
+	require.Equal(t, wall, collision.Contacts[0].Entity)
+	require.False(t, ecs.HasComponent[components.Collision](em, farWall))
+
+	transform := ecs.MustGetComponent[components.Transform](em, body)
+	transform.SetPosition(100, 0)
+	require.NoError(t, c.Update())
+
+	require.False(t, ecs.HasComponent[components.Collision](em, wall))
+	collision, ok = ecs.GetComponent[components.Collision](em, body)
+	require.True(t, ok)
+	require.Len(t, collision.Contacts, 1)
+	require.Equal(t, farWall, collision.Contacts[0].Entity)
+
+	require.NoError(t, em.Remove(farWall))
+	require.NoError(t, c.Update())
+	require.False(t, c.static.Contains(farWall))
+}
+
+func TestCollisionSystem_MultipleContacts(t *testing.T) {
+	em, c := testCollisionSystem(t)
+
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+	floor := testCollider(t, em, false, 0, -8, 100, 10)
+	pipe := testCollider(t, em, false, 8, 0, 10, 10)
+	other := testCollider(t, em, true, -8, 0, 10, 10)
+
+	require.NoError(t, c.Update())
+
+	collision := ecs.MustGetComponent[components.Collision](em, body)
+	require.Len(t, collision.Contacts, 3)
+	for _, entity := range []ecs.EntityID{floor, pipe, other} {
+		_, ok := collision.With(entity)
+		require.True(t, ok)
+	}
+
+	// Every contact is listed on both entities.
+	floorCollision := ecs.MustGetComponent[components.Collision](em, floor)
+	_, ok := floorCollision.With(body)
+	require.True(t, ok)
+
+	// Leaving one contact keeps the others.
+	ecs.MustGetComponent[components.Transform](em, other).SetPosition(-100, 0)
+	require.NoError(t, c.Update())
+
+	collision = ecs.MustGetComponent[components.Collision](em, body)
+	require.Len(t, collision.Contacts, 2)
+	_, ok = collision.With(other)
+	require.False(t, ok)
+	require.False(t, ecs.HasComponent[components.Collision](em, other))
+}
+
+func TestCollisionSystem_ContactManifold(t *testing.T) {
+	em, c := testCollisionSystem(t)
+
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+	wall := testCollider(t, em, false, 8, 1, 10, 10)
+
+	require.NoError(t, c.Update())
+
+	contact, ok := ecs.MustGetComponent[components.Collision](em, body).With(wall)
+	require.True(t, ok)
+	require.InDelta(t, 2, contact.Penetration, 1e-9)
+	require.Equal(t, cp.Vector{X: 1}, contact.Normal)
+
+	// The other side sees the same contact pointing back.
+	contact, ok = ecs.MustGetComponent[components.Collision](em, wall).With(body)
+	require.True(t, ok)
+	require.InDelta(t, 2, contact.Penetration, 1e-9)
+	require.Equal(t, cp.Vector{X: -1}, contact.Normal)
+}
+
+func TestCollisionResolverSystem_SeparatesBodies(t *testing.T) {
+	em := ecs.NewEntityManager()
+	systemManager := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+	c := NewCollisionSystem(0)
+	r := NewCollisionResolverSystem(1)
+	systemManager.Add(c)
+	systemManager.Add(r)
+
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+	testCollider(t, em, false, 8, 0, 10, 10)
+
+	rigidBody := ecs.MustGetComponent[components.RigidBody](em, body)
+	rigidBody.Velocity = cp.Vector{X: 10}
+
+	require.NoError(t, c.Update())
+	require.NoError(t, r.Update())
+
+	// The body is pushed out of the wall and stops moving into it.
+	transform := ecs.MustGetComponent[components.Transform](em, body)
+	require.InDelta(t, -2, transform.Position.X, 1e-9)
+	require.LessOrEqual(t, rigidBody.Velocity.X, 0.0)
+
+	// Resting against the wall is no longer a penetration.
+	require.NoError(t, c.Update())
+	contact := ecs.MustGetComponent[components.Collision](em, body).Contacts[0]
+	require.Zero(t, contact.Penetration)
+}
+
+func contactEntities(contacts []components.Contact) []ecs.EntityID {
+	entities := make([]ecs.EntityID, 0, len(contacts))
+	for _, contact := range contacts {
+		entities = append(entities, contact.Entity)
+	}
+
+	return entities
+}
+
+func TestCollisionSystem_Events(t *testing.T) {
+	em, c := testCollisionSystem(t)
+
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+	wall := testCollider(t, em, false, 8, 0, 10, 10)
+
+	require.NoError(t, c.Update())
+	events := ecs.MustGetComponent[components.CollisionEvents](em, body)
+	require.Equal(t, []ecs.EntityID{wall}, contactEntities(events.Enter))
+	require.Empty(t, events.Stay)
+	require.Empty(t, events.Exit)
+
+	require.NoError(t, c.Update())
+	events = ecs.MustGetComponent[components.CollisionEvents](em, body)
+	require.Empty(t, events.Enter)
+	require.Equal(t, []ecs.EntityID{wall}, contactEntities(events.Stay))
+
+	ecs.MustGetComponent[components.Transform](em, body).SetPosition(-100, 0)
+	require.NoError(t, c.Update())
+	require.False(t, ecs.HasComponent[components.Collision](em, body))
+	events = ecs.MustGetComponent[components.CollisionEvents](em, body)
+	require.Equal(t, []ecs.EntityID{wall}, contactEntities(events.Exit))
+	events = ecs.MustGetComponent[components.CollisionEvents](em, wall)
+	require.Equal(t, []ecs.EntityID{body}, contactEntities(events.Exit))
+
+	// Events only last for the frame they happened in.
+	require.NoError(t, c.Update())
+	require.False(t, ecs.HasComponent[components.CollisionEvents](em, body))
+	require.False(t, ecs.HasComponent[components.CollisionEvents](em, wall))
+}
+
+func TestCollisionSystem_RemovedEntityExits(t *testing.T) {
+	em, c := testCollisionSystem(t)
+
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+	wall := testCollider(t, em, false, 8, 0, 10, 10)
+
+	require.NoError(t, c.Update())
+	require.NoError(t, em.Remove(wall))
+	require.NoError(t, c.Update())
+
+	events := ecs.MustGetComponent[components.CollisionEvents](em, body)
+	require.Equal(t, []ecs.EntityID{wall}, contactEntities(events.Exit))
+}
+
+func TestCollisionSystem_MovingStaticCollider(t *testing.T) {
+	em, c := testCollisionSystem(t)
+
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+	platform := testCollider(t, em, false, 100, 0, 10, 10)
+
+	require.NoError(t, c.Update())
+	require.False(t, ecs.HasComponent[components.Collision](em, body))
+
+	// A static collider moved by its Transform collides at its new position.
+	ecs.MustGetComponent[components.Transform](em, platform).SetPosition(8, 0)
+	require.NoError(t, c.Update())
+
+	collision, ok := ecs.GetComponent[components.Collision](em, body)
+	require.True(t, ok)
+	require.Equal(t, []ecs.EntityID{platform}, contactEntities(collision.Contacts))
+
+	bounds, ok := c.static.Bounds(platform)
+	require.True(t, ok)
+	require.Equal(t, cp.BB{L: 3, B: -5, R: 13, T: 5}, bounds)
+}
+
+func TestCollisionResolverSystem_IgnoresTriggers(t *testing.T) {
+	em := ecs.NewEntityManager()
+	systemManager := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+	c := NewCollisionSystem(0)
+	r := NewCollisionResolverSystem(1)
+	systemManager.Add(c)
+	systemManager.Add(r)
+
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+	checkpoint := testCollider(t, em, false, 8, 0, 10, 10)
+	ecs.MustGetComponent[components.Collider](em, checkpoint).Trigger = true
+
+	require.NoError(t, c.Update())
+	require.NoError(t, r.Update())
+
+	events := ecs.MustGetComponent[components.CollisionEvents](em, body)
+	require.Len(t, events.Enter, 1)
+	require.True(t, events.Enter[0].Trigger)
+
+	// The body passes through the trigger.
+	require.Zero(t, ecs.MustGetComponent[components.Transform](em, body).Position.X)
+}
+
+func TestCollisionSystem_Layers(t *testing.T) {
+	em, c := testCollisionSystem(t)
+
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+	pipe := testCollider(t, em, false, 8, 0, 10, 10)
+	decoration := testCollider(t, em, false, -8, 0, 10, 10)
+
+	bodyCollider := ecs.MustGetComponent[components.Collider](em, body)
+	bodyCollider.Category = components.LayerPlayer
+	bodyCollider.Mask = components.LayerObstacle
+	ecs.MustGetComponent[components.Collider](em, pipe).Category = components.LayerObstacle
+	ecs.MustGetComponent[components.Collider](em, decoration).Category = components.LayerDecoration
+
+	require.NoError(t, c.Update())
+
+	collision := ecs.MustGetComponent[components.Collision](em, body)
+	require.Equal(t, []ecs.EntityID{pipe}, contactEntities(collision.Contacts))
+	require.False(t, ecs.HasComponent[components.Collision](em, decoration))
+}
+
+func TestCollisionSystem_Bullet(t *testing.T) {
+	for _, bullet := range []bool{false, true} {
+		t.Run(fmt.Sprintf("bullet=%t", bullet), func(t *testing.T) {
+			em := ecs.NewEntityManager()
+			systemManager := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+			c := NewCollisionSystem(0)
+			r := NewCollisionResolverSystem(1)
+			systemManager.Add(c)
+			systemManager.Add(r)
+
+			body := testCollider(t, em, true, 0, 0, 10, 10)
+			wall := testCollider(t, em, false, 50, 0, 2, 100)
+
+			rigidBody := ecs.MustGetComponent[components.RigidBody](em, body)
+			rigidBody.Bullet = bullet
+			rigidBody.Velocity = cp.Vector{X: 6000}
+
+			require.NoError(t, c.Update())
+
+			// In one update the body moves from in front of the wall to behind it.
+			transform := ecs.MustGetComponent[components.Transform](em, body)
+			transform.Translate(100, 0)
+
+			require.NoError(t, c.Update())
+			require.NoError(t, r.Update())
+
+			if !bullet {
+				require.Greater(t, transform.Position.X, 50.0)
+				require.False(t, ecs.HasComponent[components.Collision](em, body))
+
+				return
+			}
+
+			// The bullet stops at the wall and no longer moves into it.
+			_, ok := ecs.MustGetComponent[components.Collision](em, body).With(wall)
+			require.True(t, ok)
+			require.InDelta(t, 44, transform.Position.X, 1e-9)
+			require.LessOrEqual(t, rigidBody.Velocity.X, 0.0)
+		})
+	}
+}
+
+func TestCollisionResolverSystem_BodyTypes(t *testing.T) {
+	em := ecs.NewEntityManager()
+	systemManager := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+	c := NewCollisionSystem(0)
+	r := NewCollisionResolverSystem(1)
+	systemManager.Add(c)
+	systemManager.Add(r)
+
+	player := testCollider(t, em, true, 0, 0, 10, 10)
+	platform := testCollider(t, em, true, -8, 0, 10, 10)
+	wall := testCollider(t, em, true, 100, 0, 10, 10)
+	crate := testCollider(t, em, true, 100, 8, 10, 10)
+
+	platformBody := ecs.MustGetComponent[components.RigidBody](em, platform)
+	platformBody.Type = components.BodyKinematic
+	platformBody.Velocity = cp.Vector{X: 50}
+	ecs.MustGetComponent[components.RigidBody](em, wall).Type = components.BodyStatic
+
+	require.NoError(t, c.Update())
+	require.NoError(t, r.Update())
+
+	// The platform pushes the player out and along without being pushed back.
+	require.InDelta(t, 2, ecs.MustGetComponent[components.Transform](em, player).Position.X, 1e-9)
+	require.InDelta(t, -8, ecs.MustGetComponent[components.Transform](em, platform).Position.X, 1e-9)
+	require.Greater(t, ecs.MustGetComponent[components.RigidBody](em, player).Velocity.X, 0.0)
+	require.Equal(t, cp.Vector{X: 50}, platformBody.Velocity)
+
+	// Static rigid bodies are kept in the static grid and never move.
+	require.True(t, c.static.Contains(wall))
+	require.InDelta(t, 100, ecs.MustGetComponent[components.Transform](em, wall).Position.X, 1e-9)
+	require.InDelta(t, 10, ecs.MustGetComponent[components.Transform](em, crate).Position.Y, 1e-9)
+}
+
+func BenchmarkCollisionSystem_Update(b *testing.B) {
+	const (
+		dynamicCount = 16
+		wallSize     = 32.0
+		wallSpacing  = 40.0
+	)
+
+	for _, staticCount := range []int{100, 1_000, 10_000} {
+		b.Run(fmt.Sprintf("static=%d", staticCount), func(b *testing.B) {
+			em, c := testCollisionSystem(b)
+
+			side := int(math.Ceil(math.Sqrt(float64(staticCount))))
+			for i := range staticCount {
+				testCollider(b, em, false, float64(i%side)*wallSpacing, float64(i/side)*wallSpacing, wallSize, wallSize)
+			}
+
+			bodies := make([]*components.Transform, 0, dynamicCount)
+			for i := range dynamicCount {
+				// Bodies move along the corridors between walls without touching them.
+				entity := testCollider(b, em, true, float64(i)*wallSpacing+wallSpacing/2, 0, 4, 4)
+				bodies = append(bodies, ecs.MustGetComponent[components.Transform](em, entity))
+			}
+
+			b.ResetTimer()
+			for b.Loop() {
+				for _, transform := range bodies {
+					transform.Translate(0, 1)
+				}
+
+				if err := c.Update(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package physics
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/samix73/game/helpers"
+	"github.com/samix73/game/keys"
+)
+
+var (
+	debugCellColor     = color.RGBA{80, 80, 80, 96}
+	debugStaticColor   = color.RGBA{60, 140, 255, 255}
+	debugDynamicColor  = color.RGBA{80, 220, 80, 255}
+	debugTriggerColor  = color.RGBA{240, 200, 40, 255}
+	debugContactColor  = color.RGBA{255, 60, 60, 255}
+	debugVelocityColor = color.RGBA{220, 80, 220, 255}
+)
+
+const (
+	// debugVelocityScale is the time in seconds velocity vectors are drawn for.
+	debugVelocityScale = 0.1
+	debugContactRadius = 3
+)
+
+var _ ecs.DrawableSystem = (*PhysicsDebugSystem)(nil)
+
+func init() {
+	ecs.RegisterSystem(NewPhysicsDebugSystem)
+}
+
+// PhysicsDebugSystem draws the broad phase cells of the world's CollisionSystem, collider
+// bounds, contacts and velocities on top of the scene while toggled on with keys.DebugAction.
+// Worlds list it after the systems that draw the scene.
+type PhysicsDebugSystem struct {
+	*ecs.BaseSystem
+
+	enabled bool
+}
+
+func NewPhysicsDebugSystem(priority int) *PhysicsDebugSystem {
+	return &PhysicsDebugSystem{
+		BaseSystem: ecs.NewBaseSystem(priority),
+	}
+}
+
+func (d *PhysicsDebugSystem) Update() error {
+	if keys.IsPressed(keys.DebugAction) {
+		d.enabled = !d.enabled
+	}
+
+	return nil
+}
+
+// debugView maps world positions to the screen with the projection of the active camera.
+type debugView struct {
+	projection ebiten.GeoM
+	// visible is the world area covered by the viewport.
+	visible  cp.BB
+	viewport image.Rectangle
+}
+
+func newDebugView(camera *components.Camera, position cp.Vector, rotation float64, viewport image.Rectangle) debugView {
+	projection := camera.Projection(position, rotation, viewport)
+
+	return debugView{
+		projection: projection,
+		visible:    components.VisibleBounds(projection, viewport),
+		viewport:   viewport,
+	}
+}
+
+func (v debugView) toScreen(p cp.Vector) (float32, float32) {
+	x, y := v.projection.Apply(p.X, p.Y)
+	return float32(x), float32(y)
+}
+
+// bounds returns the world area visible on the screen.
+func (v debugView) bounds() cp.BB {
+	return v.visible
+}
+
+// strokeBB outlines a world box, which is not axis aligned on screen when the camera is rotated.
+func (v debugView) strokeBB(screen *ebiten.Image, bb cp.BB, clr color.Color) {
+	corners := [4]cp.Vector{{X: bb.L, Y: bb.B}, {X: bb.R, Y: bb.B}, {X: bb.R, Y: bb.T}, {X: bb.L, Y: bb.T}}
+	for i, corner := range corners {
+		v.strokeLine(screen, corner, corners[(i+1)%len(corners)], clr)
+	}
+}
+
+func (v debugView) strokeLine(screen *ebiten.Image, from, to cp.Vector, clr color.Color) {
+	x0, y0 := v.toScreen(from)
+	x1, y1 := v.toScreen(to)
+	vector.StrokeLine(screen, x0, y0, x1, y1, 1, clr, false)
+}
+
+// contactPoint approximates where two colliders touch by the center of their overlap.
+func contactPoint(a, b cp.BB) cp.Vector {
+	overlap := cp.BB{
+		L: max(a.L, b.L), B: max(a.B, b.B),
+		R: min(a.R, b.R), T: min(a.T, b.T),
+	}
+
+	return overlap.Center()
+}
+
+// view returns the view of the active camera, the overlay is drawn over its viewport.
+func (d *PhysicsDebugSystem) view(em *ecs.EntityManager) debugView {
+	cfg := d.Game().Config()
+
+	camera, ok := helpers.First(ecs.Query[components.ActiveCamera](em))
+	if !ok {
+		return newDebugView(&components.Camera{}, cp.Vector{}, 0, image.Rect(0, 0, cfg.ScreenWidth, cfg.ScreenHeight))
+	}
+
+	cameraComponent := &components.Camera{}
+	if c, ok := ecs.GetComponent[components.Camera](em, camera); ok {
+		cameraComponent = c
+	}
+
+	var position cp.Vector
+	var rotation float64
+	if transform, ok := ecs.GetComponent[components.Transform](em, camera); ok {
+		position, rotation = transform.Position, transform.Rotation
+	}
+
+	viewport := cameraComponent.ViewportRect(cfg.ScreenWidth, cfg.ScreenHeight)
+
+	return newDebugView(cameraComponent, position, rotation, viewport)
+}
+
+// renderBounds returns the bounds of a collider where it is drawn, at the Interpolation
+// of bodies that move in fixed steps and at its Transform otherwise. It returns false for
+// entities without a Collider or a Transform, such as contacts removed during the frame.
+func renderBounds(em *ecs.EntityManager, entity ecs.EntityID) (cp.BB, bool) {
+	collider, ok := ecs.GetComponent[components.Collider](em, entity)
+	if !ok {
+		return cp.BB{}, false
+	}
+
+	transform, ok := ecs.GetComponent[components.Transform](em, entity)
+	if !ok {
+		return cp.BB{}, false
+	}
+
+	position := transform.Position
+	if interpolation, ok := ecs.GetComponent[components.Interpolation](em, entity); ok {
+		position = interpolation.Position
+	}
+
+	return collider.LocalBounds().Offset(position), true
+}
+
+// drawCells outlines the occupied cells of the world's BroadPhase.
+func (d *PhysicsDebugSystem) drawCells(screen *ebiten.Image, view debugView, em *ecs.EntityManager) {
+	entity, ok := helpers.First(ecs.Query[components.BroadPhase](em))
+	if !ok {
+		return
+	}
+
+	visible := view.bounds()
+
+	ecs.MustGetComponent[components.BroadPhase](em, entity).Index.Cells(func(bb cp.BB) {
+		if bb.Intersects(visible) {
+			view.strokeBB(screen, bb, debugCellColor)
+		}
+	})
+}
+
+func (d *PhysicsDebugSystem) Draw(screen *ebiten.Image) {
+	if !d.enabled {
+		return
+	}
+
+	em := d.EntityManager()
+	view := d.view(em)
+	screen = screen.SubImage(view.viewport).(*ebiten.Image)
+	visible := view.bounds()
+
+	d.drawCells(screen, view, em)
+
+	for _, entity := range ecs.Query2[components.Collider, components.Transform](em) {
+		bounds, _ := renderBounds(em, entity)
+		if !bounds.Intersects(visible) {
+			continue
+		}
+
+		clr := debugStaticColor
+		if ecs.MustGetComponent[components.Collider](em, entity).Trigger {
+			clr = debugTriggerColor
+		} else if ecs.HasComponent[components.RigidBody](em, entity) {
+			clr = debugDynamicColor
+		}
+
+		view.strokeBB(screen, bounds, clr)
+	}
+
+	for _, entity := range ecs.Query3[components.Collision, components.Collider, components.Transform](em) {
+		bounds, _ := renderBounds(em, entity)
+		collision := ecs.MustGetComponent[components.Collision](em, entity)
+
+		for _, contact := range collision.Contacts {
+			// Both entities list the contact, draw it once from the lower entity
+			if contact.Entity < entity {
+				continue
+			}
+
+			otherBounds, ok := renderBounds(em, contact.Entity)
+			if !ok {
+				continue
+			}
+
+			point := contactPoint(bounds, otherBounds)
+			x, y := view.toScreen(point)
+			vector.FillCircle(screen, x, y, debugContactRadius, debugContactColor, false)
+			view.strokeLine(screen, point, point.Add(contact.Normal.Mult(16)), debugContactColor)
+		}
+	}
+
+	for _, entity := range ecs.Query2[components.RigidBody, components.Transform](em) {
+		position := ecs.MustGetComponent[components.Transform](em, entity).Position
+		if interpolation, ok := ecs.GetComponent[components.Interpolation](em, entity); ok {
+			position = interpolation.Position
+		}
+		velocity := ecs.MustGetComponent[components.RigidBody](em, entity).Velocity
+
+		view.strokeLine(screen, position, position.Add(velocity.Mult(debugVelocityScale)), debugVelocityColor)
+	}
+}
+
+func (d *PhysicsDebugSystem) Start() error {
+	return nil
+}
+
+func (d *PhysicsDebugSystem) Teardown() {
+}
@@ -0,0 +1,78 @@
+package physics
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugView_ToScreen(t *testing.T) {
+	view := newDebugView(&components.Camera{}, cp.Vector{X: 100, Y: 50}, 0, image.Rect(0, 0, 800, 600))
+
+	x, y := view.toScreen(cp.Vector{X: 100, Y: 50})
+	require.Equal(t, float32(400), x)
+	require.Equal(t, float32(300), y)
+
+	// World y points up, screen y points down.
+	x, y = view.toScreen(cp.Vector{X: 110, Y: 60})
+	require.Equal(t, float32(410), x)
+	require.Equal(t, float32(290), y)
+
+	require.Equal(t, cp.BB{L: -300, B: -250, R: 500, T: 350}, view.bounds())
+}
+
+func TestDebugView_ZoomAndRotation(t *testing.T) {
+	view := newDebugView(&components.Camera{Zoom: 2}, cp.Vector{}, math.Pi/2, image.Rect(0, 0, 800, 600))
+
+	// The camera is turned counter-clockwise, so the world appears turned clockwise.
+	x, y := view.toScreen(cp.Vector{Y: 10})
+	require.InDelta(t, 420, x, 1e-9)
+	require.InDelta(t, 300, y, 1e-9)
+
+	bounds := view.bounds()
+	require.InDelta(t, -150, bounds.L, 1e-9)
+	require.InDelta(t, -200, bounds.B, 1e-9)
+	require.InDelta(t, 150, bounds.R, 1e-9)
+	require.InDelta(t, 200, bounds.T, 1e-9)
+}
+
+func TestContactPoint(t *testing.T) {
+	a := cp.BB{L: -5, B: -5, R: 5, T: 5}
+	b := cp.BB{L: 3, B: -50, R: 13, T: 50}
+
+	require.Equal(t, cp.Vector{X: 4, Y: 0}, contactPoint(a, b))
+}
+
+func TestRenderBounds(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	wall := testCollider(t, em, false, 100, 0, 10, 10)
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+
+	interpolation, err := ecs.AddComponent[components.Interpolation](em, body)
+	require.NoError(t, err)
+	interpolation.Position = cp.Vector{X: -2, Y: 1}
+
+	// Bodies that step at a fixed rate are drawn between their last two steps.
+	bounds, ok := renderBounds(em, body)
+	require.True(t, ok)
+	require.Equal(t, cp.BB{L: -7, B: -4, R: 3, T: 6}, bounds)
+
+	bounds, ok = renderBounds(em, wall)
+	require.True(t, ok)
+	require.Equal(t, cp.BB{L: 95, B: -5, R: 105, T: 5}, bounds)
+
+	// Entities that lost their Transform or were removed are not drawn.
+	require.NoError(t, ecs.RemoveComponent[components.Transform](em, wall))
+	_, ok = renderBounds(em, wall)
+	require.False(t, ok)
+
+	require.NoError(t, em.Remove(body))
+	_, ok = renderBounds(em, body)
+	require.False(t, ok)
+}
@@ -0,0 +1,182 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/samix73/game/helpers"
+)
+
+const (
+	defaultTickRate    = 60.0
+	defaultMaxSubSteps = 5
+
+	// stepEpsilon absorbs rounding when the frame time is a multiple of the step time.
+	stepEpsilon = 1e-9
+)
+
+var _ ecs.System = (*FixedStepSystem)(nil)
+
+func init() {
+	ecs.RegisterSystem(NewFixedStepSystem)
+}
+
+// stepSettings returns the step time and sub step limit of a world.
+func stepSettings(em *ecs.EntityManager) (float64, int) {
+	tickRate, maxSubSteps := defaultTickRate, defaultMaxSubSteps
+
+	if entity, ok := helpers.First(ecs.Query[components.PhysicsSettings](em)); ok {
+		settings := ecs.MustGetComponent[components.PhysicsSettings](em, entity)
+		if settings.TickRate > 0 {
+			tickRate = settings.TickRate
+		}
+		if settings.MaxSubSteps > 0 {
+			maxSubSteps = settings.MaxSubSteps
+		}
+	}
+
+	return 1 / tickRate, maxSubSteps
+}
+
+// fixedTimestep accumulates frame time and turns it into a whole number of fixed steps.
+type fixedTimestep struct {
+	accumulator float64
+}
+
+// advance adds the frame time and returns the number of steps to run, at most maxSubSteps.
+func (f *fixedTimestep) advance(frameDt, stepDt float64, maxSubSteps int) int {
+	f.accumulator += max(frameDt, 0)
+
+	steps := int(math.Floor((f.accumulator + stepEpsilon) / stepDt))
+	if steps > maxSubSteps {
+		steps = maxSubSteps
+		f.accumulator = math.Mod(f.accumulator, stepDt)
+	} else {
+		f.accumulator = max(f.accumulator-float64(steps)*stepDt, 0)
+	}
+
+	return steps
+}
+
+// alpha returns how far the accumulated time is into the next step, between 0 and 1.
+func (f *fixedTimestep) alpha(stepDt float64) float64 {
+	return min(f.accumulator/stepDt, 1)
+}
+
+// capturePrevious stores the transform of every body before a step moves it.
+func capturePrevious(em *ecs.EntityManager) error {
+	for _, entity := range ecs.Query2[components.RigidBody, components.Transform](em) {
+		transform := ecs.MustGetComponent[components.Transform](em, entity)
+
+		interpolation, ok := ecs.GetComponent[components.Interpolation](em, entity)
+		if !ok {
+			var err error
+			interpolation, err = ecs.AddComponent[components.Interpolation](em, entity)
+			if err != nil {
+				return fmt.Errorf("error adding interpolation: %w", err)
+			}
+		}
+
+		interpolation.PreviousPosition = transform.Position
+		interpolation.PreviousRotation = transform.Rotation
+	}
+
+	return nil
+}
+
+// interpolate blends the render state of every body between its previous and current transform.
+func interpolate(em *ecs.EntityManager, alpha float64) {
+	for _, entity := range ecs.Query2[components.Interpolation, components.Transform](em) {
+		transform := ecs.MustGetComponent[components.Transform](em, entity)
+		interpolation := ecs.MustGetComponent[components.Interpolation](em, entity)
+
+		interpolation.Position = interpolation.PreviousPosition.Lerp(transform.Position, alpha)
+		interpolation.Rotation = interpolation.PreviousRotation + (transform.Rotation-interpolation.PreviousRotation)*alpha
+	}
+}
+
+// fixedStepper is a physics system that can be advanced by an explicit time step.
+type fixedStepper interface {
+	ecs.System
+	step(dt float64) error
+}
+
+// FixedStepSystem runs gravity, integration, joints, collision detection and collision
+// response in fixed time steps, independent of the frame rate and time scale jitter.
+// Worlds list it instead of GravitySystem, PhysicsSystem, JointSystem, CollisionSystem and
+// CollisionResolverSystem, and render after it to use the Interpolation of bodies.
+type FixedStepSystem struct {
+	*ecs.BaseSystem
+
+	timestep  fixedTimestep
+	systems   *ecs.SystemManager
+	collision *CollisionSystem
+	pipeline  []fixedStepper
+}
+
+func NewFixedStepSystem(priority int) *FixedStepSystem {
+	collision := NewCollisionSystem(3)
+
+	return &FixedStepSystem{
+		BaseSystem: ecs.NewBaseSystem(priority),
+		collision:  collision,
+		pipeline: []fixedStepper{
+			NewGravitySystem(0),
+			NewPhysicsSystem(1),
+			NewJointSystem(2),
+			collision,
+			NewCollisionResolverSystem(4),
+		},
+	}
+}
+
+func (f *FixedStepSystem) Update() error {
+	em := f.EntityManager()
+
+	stepDt, maxSubSteps := stepSettings(em)
+	steps := f.timestep.advance(f.Game().DeltaTime(), stepDt, maxSubSteps)
+
+	// CollisionEvents hold the events of every step of this frame, and none without a step.
+	if err := f.collision.clearEvents(em); err != nil {
+		return fmt.Errorf("physics.FixedStepSystem.Update: %w", err)
+	}
+
+	for range steps {
+		if err := capturePrevious(em); err != nil {
+			return fmt.Errorf("physics.FixedStepSystem.Update: %w", err)
+		}
+
+		for _, system := range f.pipeline {
+			if err := system.step(stepDt); err != nil {
+				return fmt.Errorf("physics.FixedStepSystem.Update: %w", err)
+			}
+		}
+	}
+
+	interpolate(em, f.timestep.alpha(stepDt))
+
+	return nil
+}
+
+func (f *FixedStepSystem) Start() error {
+	// The pipeline gets its own manager so the systems share this system's world.
+	f.systems = ecs.NewSystemManager(f.EntityManager(), f.Game())
+
+	for _, system := range f.pipeline {
+		f.systems.Add(system)
+
+		if err := system.Start(); err != nil {
+			return fmt.Errorf("physics.FixedStepSystem.Start: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (f *FixedStepSystem) Teardown() {
+	if f.systems != nil {
+		f.systems.Teardown()
+	}
+}
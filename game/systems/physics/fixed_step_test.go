@@ -0,0 +1,112 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixedTimestep_Advance(t *testing.T) {
+	const stepDt = 1.0 / 60
+
+	var f fixedTimestep
+
+	// Half a step is accumulated without stepping.
+	require.Zero(t, f.advance(stepDt/2, stepDt, 5))
+	require.InDelta(t, 0.5, f.alpha(stepDt), 1e-9)
+
+	require.Equal(t, 1, f.advance(stepDt, stepDt, 5))
+	require.InDelta(t, 0.5, f.alpha(stepDt), 1e-9)
+
+	// A long frame is clamped and the backlog dropped.
+	require.Equal(t, 5, f.advance(stepDt*20, stepDt, 5))
+	require.Less(t, f.alpha(stepDt), 1.0)
+
+	// Paused games do not step.
+	f = fixedTimestep{}
+	require.Zero(t, f.advance(0, stepDt, 5))
+}
+
+func testFixedStepSystem(t *testing.T) (*ecs.EntityManager, *ecs.Game, *FixedStepSystem) {
+	t.Helper()
+
+	em := ecs.NewEntityManager()
+	game := ecs.NewGame(&ecs.GameConfig{})
+	systemManager := ecs.NewSystemManager(em, game)
+
+	f := NewFixedStepSystem(0)
+	systemManager.Add(f)
+	require.NoError(t, f.Start())
+
+	return em, game, f
+}
+
+func TestFixedStepSystem_IndependentOfTimeScale(t *testing.T) {
+	jump := func(timeScale float64, frames int) cp.Vector {
+		em, game, f := testFixedStepSystem(t)
+		game.SetTimeScale(timeScale)
+
+		body := testCollider(t, em, true, 0, 0, 10, 10)
+		ecs.MustGetComponent[components.RigidBody](em, body).Velocity = cp.Vector{X: 100, Y: 500}
+
+		for range frames {
+			require.NoError(t, f.Update())
+		}
+
+		return ecs.MustGetComponent[components.Transform](em, body).Position
+	}
+
+	full := jump(1, 30)
+	half := jump(0.5, 60)
+
+	require.InDelta(t, full.X, half.X, 1e-9)
+	require.InDelta(t, full.Y, half.Y, 1e-9)
+}
+
+func TestFixedStepSystem_Interpolation(t *testing.T) {
+	em, game, f := testFixedStepSystem(t)
+	game.SetTimeScale(0.5)
+
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+	rigidBody := ecs.MustGetComponent[components.RigidBody](em, body)
+	rigidBody.Gravity = false
+	rigidBody.Velocity = cp.Vector{X: 60}
+
+	// Half a step: nothing moved yet.
+	require.NoError(t, f.Update())
+	require.False(t, ecs.HasComponent[components.Interpolation](em, body))
+
+	// One step moves the body by a full step, the render state stays in between.
+	require.NoError(t, f.Update())
+	require.NoError(t, f.Update())
+
+	transform := ecs.MustGetComponent[components.Transform](em, body)
+	interpolation := ecs.MustGetComponent[components.Interpolation](em, body)
+	require.InDelta(t, 1, transform.Position.X, 1e-9)
+	require.InDelta(t, 0.5, interpolation.Position.X, 1e-9)
+}
+
+func TestFixedStepSystem_EventsOfEveryStep(t *testing.T) {
+	em, game, f := testFixedStepSystem(t)
+	game.SetTimeScale(2)
+
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+	ecs.MustGetComponent[components.RigidBody](em, body).Gravity = false
+	trigger := testCollider(t, em, false, 0, 0, 20, 20)
+	ecs.MustGetComponent[components.Collider](em, trigger).Trigger = true
+
+	// The contact starts in the first of two steps and stays in the second, both are reported.
+	require.NoError(t, f.Update())
+	events := ecs.MustGetComponent[components.CollisionEvents](em, body)
+	require.Len(t, events.Enter, 1)
+	require.Equal(t, trigger, events.Enter[0].Entity)
+	require.Len(t, events.Stay, 1)
+
+	// A frame without a step has no events.
+	game.SetTimeScale(0)
+	require.NoError(t, f.Update())
+	require.False(t, ecs.HasComponent[components.CollisionEvents](em, body))
+}
@@ -4,8 +4,10 @@ import (
 	"github.com/jakecoffman/cp"
 	ecs "github.com/samix73/ebiten-ecs"
 	"github.com/samix73/game/game/components"
+	"github.com/samix73/game/helpers"
 )
 
+// gravity is used by worlds without a WorldGravity entity.
 var gravity = cp.Vector{X: 0, Y: -981}
 
 var _ ecs.System = (*GravitySystem)(nil)
@@ -14,6 +16,71 @@ func init() {
 	ecs.RegisterSystem(NewGravitySystem)
 }
 
+type gravityZone struct {
+	entity       ecs.EntityID
+	bounds       cp.BB
+	acceleration cp.Vector
+	priority     int
+}
+
+// gravityField resolves the gravity at a position from the world gravity and gravity zones.
+type gravityField struct {
+	world cp.Vector
+	zones []gravityZone
+}
+
+// newGravityField reads the WorldGravity and GravityZone entities of a world.
+// fallback is used when the world has no WorldGravity.
+func newGravityField(em *ecs.EntityManager, fallback cp.Vector) gravityField {
+	field := gravityField{world: fallback}
+
+	if entity, ok := helpers.First(ecs.Query[components.WorldGravity](em)); ok {
+		field.world = ecs.MustGetComponent[components.WorldGravity](em, entity).Acceleration
+	}
+
+	for _, entity := range ecs.Query[components.GravityZone](em) {
+		zone := ecs.MustGetComponent[components.GravityZone](em, entity)
+
+		bounds := zone.Bounds
+		if transform, ok := ecs.GetComponent[components.Transform](em, entity); ok {
+			bounds = bounds.Offset(transform.Position)
+		}
+
+		field.zones = append(field.zones, gravityZone{
+			entity:       entity,
+			bounds:       bounds,
+			acceleration: zone.Acceleration,
+			priority:     zone.Priority,
+		})
+	}
+
+	return field
+}
+
+// At returns the gravity at position. Overlapping zones with the same priority
+// are resolved in favor of the oldest entity so the result does not depend on query order.
+func (f gravityField) At(position cp.Vector) cp.Vector {
+	var found *gravityZone
+	for i := range f.zones {
+		zone := &f.zones[i]
+		if !zone.bounds.ContainsVect(position) {
+			continue
+		}
+
+		if found == nil || zone.priority > found.priority ||
+			(zone.priority == found.priority && zone.entity < found.entity) {
+			found = zone
+		}
+	}
+
+	if found == nil {
+		return f.world
+	}
+
+	return found.acceleration
+}
+
+// GravitySystem accelerates rigid bodies by the gravity at their position.
 type GravitySystem struct {
 	*ecs.BaseSystem
 
@@ -28,25 +95,27 @@ func NewGravitySystem(priority int) *GravitySystem {
 }
 
 func (g *GravitySystem) Update() error {
+	return g.step(g.Game().DeltaTime())
+}
+
+func (g *GravitySystem) step(dt float64) error {
 	em := g.EntityManager()
+	field := newGravityField(em, g.dv)
+
 	for _, entity := range ecs.Query[components.RigidBody](em) {
 		rigidBody := ecs.MustGetComponent[components.RigidBody](em, entity)
-		if rigidBody == nil {
-			continue
-		}
 
-		if !rigidBody.Gravity {
+		scale := rigidBody.GravityMultiplier()
+		if scale == 0 {
 			continue
 		}
 
-		game := g.Game()
-
-		acc := cp.Vector{
-			X: g.dv.X * game.DeltaTime(),
-			Y: g.dv.Y * game.DeltaTime(),
+		var position cp.Vector
+		if transform, ok := ecs.GetComponent[components.Transform](em, entity); ok {
+			position = transform.Position
 		}
 
-		rigidBody.ApplyAcceleration(acc)
+		rigidBody.ApplyAcceleration(field.At(position).Mult(scale * dt))
 	}
 
 	return nil
@@ -3,6 +3,7 @@ package physics
 import (
 	"testing"
 
+	"github.com/BurntSushi/toml"
 	"github.com/jakecoffman/cp"
 	ecs "github.com/samix73/ebiten-ecs"
 	"github.com/samix73/game/game/components"
@@ -57,3 +58,116 @@ func TestGravity_Update(t *testing.T) {
 	require.Equal(t, cp.Vector{X: 0, Y: 0}, disableGravityRigidBody.Velocity)
 
 }
+
+func TestGravity_WorldGravityAndZones(t *testing.T) {
+	em := ecs.NewEntityManager()
+	game := ecs.NewGame(&ecs.GameConfig{})
+	systemManager := ecs.NewSystemManager(em, game)
+
+	g := NewGravitySystem(0)
+	systemManager.Add(g)
+
+	world, err := em.NewEntity()
+	require.NoError(t, err)
+	worldGravity, err := ecs.AddComponent[components.WorldGravity](em, world)
+	require.NoError(t, err)
+	worldGravity.Acceleration = cp.Vector{Y: -10}
+
+	addZone := func(x float64, acceleration cp.Vector, priority int) {
+		zone, err := em.NewEntity()
+		require.NoError(t, err)
+
+		transform, err := ecs.AddComponent[components.Transform](em, zone)
+		require.NoError(t, err)
+		transform.SetPosition(x, 0)
+
+		gravityZone, err := ecs.AddComponent[components.GravityZone](em, zone)
+		require.NoError(t, err)
+		gravityZone.Bounds = cp.BB{L: -50, B: -50, R: 50, T: 50}
+		gravityZone.Acceleration = acceleration
+		gravityZone.Priority = priority
+	}
+	addZone(100, cp.Vector{X: 5}, 0)
+	addZone(150, cp.Vector{Y: 20}, 1)
+
+	addBody := func(x float64) *components.RigidBody {
+		entity := testRigidbodyEntity(t, em, true)
+
+		transform, err := ecs.AddComponent[components.Transform](em, entity)
+		require.NoError(t, err)
+		transform.SetPosition(x, 0)
+
+		return ecs.MustGetComponent[components.RigidBody](em, entity)
+	}
+	outside := addBody(-100)
+	outside.GravityScale = 2
+	inZone := addBody(90)
+	inOverlap := addBody(120)
+	unscaled := addBody(-100)
+	weightless := addBody(-100)
+	weightless.Gravity = false
+
+	require.NoError(t, g.Update())
+
+	dt := game.DeltaTime()
+	require.InDelta(t, -20*dt, outside.Velocity.Y, 1e-9)
+	require.InDelta(t, 5*dt, inZone.Velocity.X, 1e-9)
+	require.InDelta(t, 20*dt, inOverlap.Velocity.Y, 1e-9)
+	require.Zero(t, inOverlap.Velocity.X)
+	require.InDelta(t, -10*dt, unscaled.Velocity.Y, 1e-9)
+	require.Zero(t, weightless.Velocity.Y)
+}
+
+func TestGravity_BodyTypes(t *testing.T) {
+	em := ecs.NewEntityManager()
+	systemManager := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+	g := NewGravitySystem(0)
+	p := NewPhysicsSystem(1)
+	systemManager.Add(g)
+	systemManager.Add(p)
+
+	bodies := make(map[components.BodyType]ecs.EntityID)
+	for _, bodyType := range []components.BodyType{components.BodyDynamic, components.BodyKinematic, components.BodyStatic} {
+		entity := testRigidbodyEntity(t, em, true)
+		_, err := ecs.AddComponent[components.Transform](em, entity)
+		require.NoError(t, err)
+
+		rigidBody := ecs.MustGetComponent[components.RigidBody](em, entity)
+		rigidBody.Type = bodyType
+		rigidBody.Velocity = cp.Vector{X: 60}
+
+		bodies[bodyType] = entity
+	}
+
+	require.NoError(t, g.Update())
+	require.NoError(t, p.Update())
+
+	dynamic := ecs.MustGetComponent[components.RigidBody](em, bodies[components.BodyDynamic])
+	require.Negative(t, dynamic.Velocity.Y)
+
+	kinematic := ecs.MustGetComponent[components.RigidBody](em, bodies[components.BodyKinematic])
+	require.Zero(t, kinematic.Velocity.Y)
+	require.Positive(t, ecs.MustGetComponent[components.Transform](em, bodies[components.BodyKinematic]).Position.X)
+
+	require.Zero(t, ecs.MustGetComponent[components.Transform](em, bodies[components.BodyStatic]).Position.X)
+}
+
+func TestRigidBody_GravityScaleTOML(t *testing.T) {
+	// Components loaded from TOML start from the zero value, an omitted or zero scale is 1
+	// and turning Gravity off makes a body weightless.
+	var unscaled components.RigidBody
+	_, err := toml.Decode(`Gravity = true`, &unscaled)
+	require.NoError(t, err)
+	require.InDelta(t, 1, unscaled.GravityMultiplier(), 1e-9)
+
+	var scaled components.RigidBody
+	_, err = toml.Decode("Gravity = true\nGravityScale = 0.5", &scaled)
+	require.NoError(t, err)
+	require.InDelta(t, 0.5, scaled.GravityMultiplier(), 1e-9)
+
+	var weightless components.RigidBody
+	_, err = toml.Decode("Gravity = false\nGravityScale = 2", &weightless)
+	require.NoError(t, err)
+	require.Zero(t, weightless.GravityMultiplier())
+}
@@ -0,0 +1,297 @@
+package physics
+
+import (
+	"fmt"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+)
+
+// jointIterations is how often the rigid joints are solved per step, so chains of joints
+// settle without stretching.
+const jointIterations = 4
+
+var _ ecs.System = (*JointSystem)(nil)
+
+func init() {
+	ecs.RegisterSystem(NewJointSystem)
+}
+
+// jointState is what the JointSystem keeps about a joint between steps.
+type jointState struct {
+	// joint is the Joint the state was created from, used to detect changes.
+	joint components.Joint
+	// distance is the distance between the anchors before the step the joint was
+	// first simulated in, which pin joints keep.
+	distance float64
+}
+
+// jointBody is an entity linked by a joint.
+type jointBody struct {
+	transform     *components.Transform
+	rigidBody     *components.RigidBody
+	interpolation *components.Interpolation
+	// invMass is 0 for entities that joints can't move, like colliders without a
+	// RigidBody and kinematic or static bodies.
+	invMass float64
+}
+
+func jointBodyOf(em *ecs.EntityManager, entity ecs.EntityID) (jointBody, bool) {
+	transform, ok := ecs.GetComponent[components.Transform](em, entity)
+	if !ok {
+		return jointBody{}, false
+	}
+
+	body := jointBody{transform: transform}
+	body.interpolation, _ = ecs.GetComponent[components.Interpolation](em, entity)
+	if rigidBody, ok := ecs.GetComponent[components.RigidBody](em, entity); ok {
+		body.rigidBody = rigidBody
+		if rigidBody.Type == components.BodyDynamic && rigidBody.Mass > 0 {
+			body.invMass = 1 / rigidBody.Mass
+		}
+	}
+
+	return body, true
+}
+
+// anchor returns an anchor relative to the entity in world space.
+func (b jointBody) anchor(local cp.Vector) cp.Vector {
+	return b.transform.Position.Add(local.Rotate(cp.ForAngle(b.transform.Rotation)))
+}
+
+// previousAnchor returns an anchor where it was before FixedStepSystem's current step moved
+// the entity, or where it is now outside of FixedStepSystem.
+func (b jointBody) previousAnchor(local cp.Vector) cp.Vector {
+	if b.interpolation == nil {
+		return b.anchor(local)
+	}
+
+	return b.interpolation.PreviousPosition.Add(local.Rotate(cp.ForAngle(b.interpolation.PreviousRotation)))
+}
+
+func (b jointBody) velocity() cp.Vector {
+	if b.rigidBody == nil {
+		return cp.Vector{}
+	}
+
+	return b.rigidBody.Velocity
+}
+
+// correct moves the bodies towards each other by position and removes velocity from their
+// relative velocity, each in proportion to its inverse mass.
+func correct(a, b jointBody, position, velocity cp.Vector) {
+	total := a.invMass + b.invMass
+
+	if a.invMass > 0 {
+		share := a.invMass / total
+		a.transform.Translate(position.X*share, position.Y*share)
+		a.rigidBody.Velocity = a.rigidBody.Velocity.Add(velocity.Mult(share))
+	}
+
+	if b.invMass > 0 {
+		share := b.invMass / total
+		b.transform.Translate(-position.X*share, -position.Y*share)
+		b.rigidBody.Velocity = b.rigidBody.Velocity.Sub(velocity.Mult(share))
+	}
+}
+
+// solveJoint enforces a pin, slide, rope or pivot joint once.
+func solveJoint(state *jointState, a, b jointBody) {
+	joint := state.joint
+	delta := b.anchor(joint.AnchorB).Sub(a.anchor(joint.AnchorA))
+	relative := b.velocity().Sub(a.velocity())
+
+	if joint.Type == components.JointPivot {
+		correct(a, b, delta, relative)
+		return
+	}
+
+	distance := delta.Length()
+	if distance == 0 {
+		return
+	}
+
+	minDistance, maxDistance := state.distance, state.distance
+	switch joint.Type {
+	case components.JointSlide:
+		minDistance, maxDistance = joint.Min, joint.Max
+	case components.JointRope:
+		minDistance, maxDistance = 0, joint.Max
+	}
+
+	var stretch float64
+	if distance < minDistance {
+		stretch = distance - minDistance
+	} else if distance > maxDistance {
+		stretch = distance - maxDistance
+	}
+
+	// Pin joints hold the distance, slide and rope joints only stop the bodies from
+	// moving further past their limit.
+	normal := delta.Mult(1 / distance)
+	speed := relative.Dot(normal)
+	if minDistance != maxDistance && (stretch == 0 || (stretch > 0) != (speed > 0)) {
+		speed = 0
+	}
+
+	correct(a, b, normal.Mult(stretch), normal.Mult(speed))
+}
+
+// applySpring pulls the bodies of a spring joint towards RestLength for one step.
+func applySpring(joint components.Joint, a, b jointBody, dt float64) {
+	delta := b.anchor(joint.AnchorB).Sub(a.anchor(joint.AnchorA))
+	distance := delta.Length()
+	if distance == 0 {
+		return
+	}
+
+	normal := delta.Mult(1 / distance)
+	speed := b.velocity().Sub(a.velocity()).Dot(normal)
+	impulse := normal.Mult((joint.Stiffness*(distance-joint.RestLength) + joint.Damping*speed) * dt)
+
+	if a.invMass > 0 {
+		a.rigidBody.Velocity = a.rigidBody.Velocity.Add(impulse.Mult(a.invMass))
+	}
+	if b.invMass > 0 {
+		b.rigidBody.Velocity = b.rigidBody.Velocity.Sub(impulse.Mult(b.invMass))
+	}
+}
+
+// JointSystem enforces Joint constraints for the hand-written pipeline, like SpaceSystem
+// does with Chipmunk constraints. It corrects the positions and velocities PhysicsSystem
+// integrated, so worlds list it between PhysicsSystem and CollisionSystem, where
+// FixedStepSystem runs it. Bodies don't rotate in this pipeline, so pivot joints hold
+// their bodies together instead of letting them swing. Anchors turn with the Transform
+// rotation of their entity.
+type JointSystem struct {
+	*ecs.BaseSystem
+
+	joints map[ecs.EntityID]*jointState
+}
+
+func NewJointSystem(priority int) *JointSystem {
+	return &JointSystem{
+		BaseSystem: ecs.NewBaseSystem(priority),
+		joints:     make(map[ecs.EntityID]*jointState),
+	}
+}
+
+func (j *JointSystem) Update() error {
+	return j.step(j.Game().DeltaTime())
+}
+
+// linkedJoint is a joint whose linked entities can be simulated this step.
+type linkedJoint struct {
+	state *jointState
+	a, b  jointBody
+}
+
+func (j *JointSystem) step(dt float64) error {
+	em := j.EntityManager()
+
+	entities := ecs.Query[components.Joint](em)
+	if len(entities) == 0 {
+		clear(j.joints)
+		return nil
+	}
+
+	linked := make([]linkedJoint, 0, len(entities))
+	orphans := make([]ecs.EntityID, 0)
+	seen := make(map[ecs.EntityID]struct{}, len(entities))
+
+	for _, entity := range entities {
+		joint := *ecs.MustGetComponent[components.Joint](em, entity)
+		if err := joint.Validate(); err != nil {
+			return fmt.Errorf("physics.JointSystem.step: joint %d: %w", entity, err)
+		}
+
+		if !linkedEntityExists(em, joint.EntityA) || !linkedEntityExists(em, joint.EntityB) {
+			orphans = append(orphans, entity)
+			continue
+		}
+
+		a, okA := jointBodyOf(em, joint.EntityA)
+		b, okB := jointBodyOf(em, joint.EntityB)
+		if !okA || !okB || a.invMass+b.invMass == 0 {
+			continue
+		}
+
+		seen[entity] = struct{}{}
+
+		state, ok := j.joints[entity]
+		if !ok || state.joint != joint {
+			state = &jointState{
+				joint:    joint,
+				distance: b.previousAnchor(joint.AnchorB).Sub(a.previousAnchor(joint.AnchorA)).Length(),
+			}
+			j.joints[entity] = state
+		}
+
+		if joint.Type == components.JointSpring {
+			applySpring(joint, a, b, dt)
+			continue
+		}
+
+		linked = append(linked, linkedJoint{state: state, a: a, b: b})
+	}
+
+	for range jointIterations {
+		for _, l := range linked {
+			solveJoint(l.state, l.a, l.b)
+		}
+	}
+
+	for entity := range j.joints {
+		if _, ok := seen[entity]; !ok {
+			delete(j.joints, entity)
+		}
+	}
+
+	for _, entity := range orphans {
+		if err := em.Remove(entity); err != nil {
+			return fmt.Errorf("physics.JointSystem.step: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (j *JointSystem) Start() error {
+	return nil
+}
+
+func (j *JointSystem) Teardown() {
+}
+
+// entityPair is an unordered pair of entities.
+type entityPair struct {
+	a, b ecs.EntityID
+}
+
+func newEntityPair(a, b ecs.EntityID) entityPair {
+	if a > b {
+		a, b = b, a
+	}
+
+	return entityPair{a: a, b: b}
+}
+
+// jointExclusions returns the pairs of entities linked by a joint without Collide,
+// which don't collide with each other.
+func jointExclusions(em *ecs.EntityManager) map[entityPair]struct{} {
+	entities := ecs.Query[components.Joint](em)
+	if len(entities) == 0 {
+		return nil
+	}
+
+	excluded := make(map[entityPair]struct{}, len(entities))
+	for _, entity := range entities {
+		joint := ecs.MustGetComponent[components.Joint](em, entity)
+		if !joint.Collide {
+			excluded[newEntityPair(joint.EntityA, joint.EntityB)] = struct{}{}
+		}
+	}
+
+	return excluded
+}
@@ -0,0 +1,177 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJointSystem_Rope(t *testing.T) {
+	em, _, f := testFixedStepSystem(t)
+
+	anchor, err := em.NewEntity()
+	require.NoError(t, err)
+	testColliderEntity(t, em, anchor, 0, 0, 2, 2)
+
+	weight := testRigidbodyEntity(t, em, true)
+	testColliderEntity(t, em, weight, 0, -20, 10, 10)
+
+	joint := testJointEntity(t, em, components.JointRope, anchor, weight)
+	ecs.MustGetComponent[components.Joint](em, joint).Max = 40
+
+	for range 120 {
+		require.NoError(t, f.Update())
+	}
+
+	// The weight falls freely until the rope is taut, then hangs still.
+	transform := ecs.MustGetComponent[components.Transform](em, weight)
+	require.InDelta(t, -40, transform.Position.Y, 1)
+	require.InDelta(t, 0, ecs.MustGetComponent[components.RigidBody](em, weight).Velocity.Y, 1e-6)
+}
+
+func TestJointSystem_Pivot(t *testing.T) {
+	em, _, f := testFixedStepSystem(t)
+
+	a := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, a, 0, 0, 10, 10)
+	b := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, b, 50, 0, 10, 10)
+	ecs.MustGetComponent[components.RigidBody](em, b).Velocity = cp.Vector{Y: 60}
+
+	joint := testJointEntity(t, em, components.JointPivot, a, b)
+	ecs.MustGetComponent[components.Joint](em, joint).AnchorA.X = 25
+	ecs.MustGetComponent[components.Joint](em, joint).AnchorB.X = -25
+
+	for range 30 {
+		require.NoError(t, f.Update())
+	}
+
+	// Bodies don't rotate, so the pivot holds them together and they share the momentum.
+	positionA := ecs.MustGetComponent[components.Transform](em, a).Position
+	positionB := ecs.MustGetComponent[components.Transform](em, b).Position
+	require.InDelta(t, 50, positionB.X-positionA.X, 1e-6)
+	require.InDelta(t, 0, positionB.Y-positionA.Y, 1e-6)
+	require.InDelta(t, 30, ecs.MustGetComponent[components.RigidBody](em, a).Velocity.Y, 1e-6)
+}
+
+func TestJointSystem_Pin(t *testing.T) {
+	em, _, f := testFixedStepSystem(t)
+
+	a := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, a, 0, 0, 10, 10)
+	b := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, b, 30, 0, 10, 10)
+	ecs.MustGetComponent[components.RigidBody](em, b).Velocity = cp.Vector{X: 60, Y: 60}
+
+	testJointEntity(t, em, components.JointPin, a, b)
+
+	for range 60 {
+		require.NoError(t, f.Update())
+	}
+
+	// The bodies keep their distance and their total momentum.
+	positionA := ecs.MustGetComponent[components.Transform](em, a).Position
+	positionB := ecs.MustGetComponent[components.Transform](em, b).Position
+	require.InDelta(t, 30, positionB.Sub(positionA).Length(), 1e-6)
+
+	momentum := ecs.MustGetComponent[components.RigidBody](em, a).Velocity.Add(ecs.MustGetComponent[components.RigidBody](em, b).Velocity)
+	require.InDelta(t, 60, momentum.X, 1e-6)
+	require.InDelta(t, 60, momentum.Y, 1e-6)
+}
+
+func TestJointSystem_Spring(t *testing.T) {
+	em, _, f := testFixedStepSystem(t)
+
+	anchor, err := em.NewEntity()
+	require.NoError(t, err)
+	testColliderEntity(t, em, anchor, 0, 0, 2, 2)
+
+	body := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, body, 100, 0, 10, 10)
+
+	joint := ecs.MustGetComponent[components.Joint](em, testJointEntity(t, em, components.JointSpring, anchor, body))
+	joint.RestLength = 50
+	joint.Stiffness = 20
+	joint.Damping = 10
+
+	for range 600 {
+		require.NoError(t, f.Update())
+	}
+
+	// The damped spring settles at its rest length.
+	require.InDelta(t, 50, ecs.MustGetComponent[components.Transform](em, body).Position.X, 0.5)
+}
+
+func TestJointSystem_Collide(t *testing.T) {
+	for _, collide := range []bool{false, true} {
+		em, _, f := testFixedStepSystem(t)
+
+		a := testRigidbodyEntity(t, em, false)
+		testColliderEntity(t, em, a, 0, 0, 10, 10)
+		b := testRigidbodyEntity(t, em, false)
+		testColliderEntity(t, em, b, 5, 0, 10, 10)
+
+		joint := ecs.MustGetComponent[components.Joint](em, testJointEntity(t, em, components.JointSlide, a, b))
+		joint.Max = 20
+		joint.Collide = collide
+
+		require.NoError(t, f.Update())
+
+		// Linked entities only collide when the joint lets them.
+		require.Equal(t, collide, ecs.HasComponent[components.Collision](em, a), "collide %v", collide)
+	}
+}
+
+func TestJointSystem_RemovesOrphanedJoints(t *testing.T) {
+	em, _, f := testFixedStepSystem(t)
+
+	a := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, a, 0, 0, 10, 10)
+	b := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, b, 20, 0, 10, 10)
+
+	joint := testJointEntity(t, em, components.JointPin, a, b)
+
+	require.NoError(t, f.Update())
+	require.NoError(t, em.Remove(b))
+	require.NoError(t, f.Update())
+
+	require.False(t, ecs.HasComponent[components.Joint](em, joint))
+}
+
+func TestJointSystem_InvalidJoints(t *testing.T) {
+	tests := []struct {
+		name string
+		link func(a, b ecs.EntityID) (ecs.EntityID, ecs.EntityID)
+	}{
+		{name: "itself", link: func(a, _ ecs.EntityID) (ecs.EntityID, ecs.EntityID) { return a, a }},
+		{name: "no entity", link: func(a, _ ecs.EntityID) (ecs.EntityID, ecs.EntityID) { return a, 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			em, _, f := testFixedStepSystem(t)
+
+			a := testRigidbodyEntity(t, em, false)
+			testColliderEntity(t, em, a, 0, 0, 10, 10)
+			b := testRigidbodyEntity(t, em, false)
+			testColliderEntity(t, em, b, 20, 0, 10, 10)
+
+			entityA, entityB := tt.link(a, b)
+			testJointEntity(t, em, components.JointPin, entityA, entityB)
+
+			require.Error(t, f.Update())
+
+			em, s := testSpaceSystem(t)
+			a = testRigidbodyEntity(t, em, false)
+			testColliderEntity(t, em, a, 0, 0, 10, 10)
+			entityA, entityB = tt.link(a, a)
+			testJointEntity(t, em, components.JointPin, entityA, entityB)
+
+			require.Error(t, s.Update())
+		})
+	}
+}
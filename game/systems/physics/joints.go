@@ -0,0 +1,128 @@
+package physics
+
+import (
+	"fmt"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+)
+
+// linkedEntityExists reports whether an entity linked by a joint is still in the world.
+// Joints move entities by their Transform, so an entity without one counts as removed.
+func linkedEntityExists(em *ecs.EntityManager, entity ecs.EntityID) bool {
+	_, ok := ecs.GetComponent[components.Transform](em, entity)
+	return ok
+}
+
+// spaceJoint links a Joint entity to its Chipmunk constraint.
+type spaceJoint struct {
+	constraint *cp.Constraint
+	// joint is the Joint the constraint was created from, used to detect changes.
+	joint components.Joint
+}
+
+// newConstraint creates the Chipmunk constraint of a joint between two bodies.
+func newConstraint(joint components.Joint, a, b *cp.Body) *cp.Constraint {
+	var constraint *cp.Constraint
+	switch joint.Type {
+	case components.JointSlide:
+		constraint = cp.NewSlideJoint(a, b, joint.AnchorA, joint.AnchorB, joint.Min, joint.Max)
+	case components.JointPivot:
+		constraint = cp.NewPivotJoint2(a, b, joint.AnchorA, joint.AnchorB)
+	case components.JointSpring:
+		constraint = cp.NewDampedSpring(a, b, joint.AnchorA, joint.AnchorB, joint.RestLength, joint.Stiffness, joint.Damping)
+	case components.JointRope:
+		constraint = cp.NewSlideJoint(a, b, joint.AnchorA, joint.AnchorB, 0, joint.Max)
+	default:
+		constraint = cp.NewPinJoint(a, b, joint.AnchorA, joint.AnchorB)
+	}
+
+	constraint.SetCollideBodies(joint.Collide)
+
+	return constraint
+}
+
+func (s *SpaceSystem) addJoint(entity ecs.EntityID, joint components.Joint, a, b *spaceBody) {
+	constraint := newConstraint(joint, a.body, b.body)
+
+	s.space.AddConstraint(constraint)
+	s.joints[entity] = &spaceJoint{constraint: constraint, joint: joint}
+}
+
+func (s *SpaceSystem) removeJoint(entity ecs.EntityID) {
+	sj, ok := s.joints[entity]
+	if !ok {
+		return
+	}
+
+	s.space.RemoveConstraint(sj.constraint)
+	delete(s.joints, entity)
+}
+
+// removeJointsOf removes the constraints attached to an entity's body,
+// they are created again once both bodies are back in the space.
+func (s *SpaceSystem) removeJointsOf(entity ecs.EntityID) {
+	for jointEntity, sj := range s.joints {
+		if sj.joint.EntityA == entity || sj.joint.EntityB == entity {
+			s.removeJoint(jointEntity)
+		}
+	}
+}
+
+// syncJoints creates, updates and removes constraints to match the Joint entities.
+// Joint entities whose linked entities were removed are removed as well.
+func (s *SpaceSystem) syncJoints(em *ecs.EntityManager) error {
+	entities := ecs.Query[components.Joint](em)
+	if len(entities) == 0 && len(s.joints) == 0 {
+		return nil
+	}
+
+	seen := make(map[ecs.EntityID]struct{}, len(s.joints))
+	orphans := make([]ecs.EntityID, 0)
+
+	for _, entity := range entities {
+		joint := *ecs.MustGetComponent[components.Joint](em, entity)
+		if err := joint.Validate(); err != nil {
+			return fmt.Errorf("physics.SpaceSystem.syncJoints: joint %d: %w", entity, err)
+		}
+
+		if !linkedEntityExists(em, joint.EntityA) || !linkedEntityExists(em, joint.EntityB) {
+			orphans = append(orphans, entity)
+			continue
+		}
+
+		a, okA := s.bodies[joint.EntityA]
+		b, okB := s.bodies[joint.EntityB]
+		if !okA || !okB {
+			// A linked entity has no body yet, the joint is created once it has one.
+			continue
+		}
+
+		seen[entity] = struct{}{}
+
+		if sj, ok := s.joints[entity]; ok {
+			if sj.joint == joint {
+				continue
+			}
+
+			s.removeJoint(entity)
+		}
+
+		s.addJoint(entity, joint, a, b)
+	}
+
+	for entity := range s.joints {
+		if _, ok := seen[entity]; !ok {
+			s.removeJoint(entity)
+		}
+	}
+
+	for _, entity := range orphans {
+		if err := em.Remove(entity); err != nil {
+			return fmt.Errorf("physics.SpaceSystem.syncJoints: %w", err)
+		}
+	}
+
+	return nil
+}
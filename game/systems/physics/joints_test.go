@@ -0,0 +1,118 @@
+package physics
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/stretchr/testify/require"
+)
+
+func testJointEntity(t *testing.T, em *ecs.EntityManager, jointType components.JointType, a, b ecs.EntityID) ecs.EntityID {
+	t.Helper()
+
+	entityID, err := em.NewEntity()
+	require.NoError(t, err)
+	joint, err := ecs.AddComponent[components.Joint](em, entityID)
+	require.NoError(t, err)
+	joint.Type = jointType
+	joint.EntityA = a
+	joint.EntityB = b
+
+	return entityID
+}
+
+func TestSpaceSystem_PivotJoint(t *testing.T) {
+	em, s := testSpaceSystem(t)
+
+	anchor, err := em.NewEntity()
+	require.NoError(t, err)
+	testColliderEntity(t, em, anchor, 0, 0, 2, 2)
+
+	bob := testRigidbodyEntity(t, em, true)
+	testColliderEntity(t, em, bob, 50, 0, 10, 10)
+
+	joint := testJointEntity(t, em, components.JointPivot, anchor, bob)
+	ecs.MustGetComponent[components.Joint](em, joint).AnchorB.X = -50
+
+	require.NoError(t, s.Update())
+	require.Contains(t, s.joints, joint)
+
+	for range 60 {
+		require.NoError(t, s.Update())
+	}
+
+	// The bob swings around the anchor instead of falling.
+	transform := ecs.MustGetComponent[components.Transform](em, bob)
+	require.InDelta(t, 50, transform.Position.Length(), 1)
+	require.Negative(t, transform.Position.Y)
+}
+
+func TestSpaceSystem_RopeJoint(t *testing.T) {
+	em, s := testSpaceSystem(t)
+
+	anchor, err := em.NewEntity()
+	require.NoError(t, err)
+	testColliderEntity(t, em, anchor, 0, 0, 2, 2)
+
+	weight := testRigidbodyEntity(t, em, true)
+	testColliderEntity(t, em, weight, 0, -20, 10, 10)
+
+	joint := testJointEntity(t, em, components.JointRope, anchor, weight)
+	ecs.MustGetComponent[components.Joint](em, joint).Max = 40
+
+	for range 120 {
+		require.NoError(t, s.Update())
+	}
+
+	// The weight falls freely until the rope is taut.
+	transform := ecs.MustGetComponent[components.Transform](em, weight)
+	require.InDelta(t, -40, transform.Position.Y, 1)
+}
+
+func TestSpaceSystem_JointUpdates(t *testing.T) {
+	em, s := testSpaceSystem(t)
+
+	a := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, a, 0, 0, 10, 10)
+	b := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, b, 20, 0, 10, 10)
+
+	joint := testJointEntity(t, em, components.JointPin, a, b)
+
+	require.NoError(t, s.Update())
+	constraint := s.joints[joint].constraint
+
+	ecs.MustGetComponent[components.Joint](em, joint).Type = components.JointSpring
+	require.NoError(t, s.Update())
+	require.False(t, s.space.ContainsConstraint(constraint))
+	require.True(t, s.space.ContainsConstraint(s.joints[joint].constraint))
+
+	constraint = s.joints[joint].constraint
+	require.NoError(t, ecs.RemoveComponent[components.Joint](em, joint))
+	require.NoError(t, s.Update())
+	require.NotContains(t, s.joints, joint)
+	require.False(t, s.space.ContainsConstraint(constraint))
+}
+
+func TestSpaceSystem_RemovesOrphanedJoints(t *testing.T) {
+	em, s := testSpaceSystem(t)
+
+	a := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, a, 0, 0, 10, 10)
+	b := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, b, 20, 0, 10, 10)
+
+	joint := testJointEntity(t, em, components.JointPin, a, b)
+
+	require.NoError(t, s.Update())
+	constraint := s.joints[joint].constraint
+
+	require.NoError(t, em.Remove(b))
+	require.NoError(t, s.Update())
+
+	require.NotContains(t, s.joints, joint)
+	require.False(t, s.space.ContainsConstraint(constraint))
+	require.False(t, ecs.HasComponent[components.Joint](em, joint))
+	require.NotContains(t, em.Query(ecs.Bitmask{}), joint)
+}
@@ -0,0 +1,44 @@
+package physics
+
+import (
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+)
+
+// contactMaterial is the combination of the materials of two colliders in contact.
+type contactMaterial struct {
+	restitution     float64
+	staticFriction  float64
+	dynamicFriction float64
+}
+
+func materialOf(em *ecs.EntityManager, entity ecs.EntityID) components.PhysicsMaterial {
+	if material, ok := ecs.GetComponent[components.PhysicsMaterial](em, entity); ok {
+		return *material
+	}
+
+	if rigidBody, ok := ecs.GetComponent[components.RigidBody](em, entity); ok && rigidBody.Type == components.BodyDynamic {
+		return components.DefaultPhysicsMaterial()
+	}
+
+	return components.DefaultStaticPhysicsMaterial()
+}
+
+func combineMaterials(a, b components.PhysicsMaterial) contactMaterial {
+	return contactMaterial{
+		restitution:     a.RestitutionCombine.Combine(b.RestitutionCombine, a.Restitution, b.Restitution),
+		staticFriction:  a.FrictionCombine.Combine(b.FrictionCombine, a.StaticFriction, b.StaticFriction),
+		dynamicFriction: a.FrictionCombine.Combine(b.FrictionCombine, a.DynamicFriction, b.DynamicFriction),
+	}
+}
+
+// friction returns the friction impulse for a contact that needs stopImpulse to stop sliding
+// and is pressed together by normalImpulse, following Coulomb's law.
+// Contacts within the static friction cone stick, all others slide with dynamic friction.
+func (m contactMaterial) friction(stopImpulse, normalImpulse float64) float64 {
+	if stopImpulse <= m.staticFriction*normalImpulse {
+		return stopImpulse
+	}
+
+	return min(stopImpulse, m.dynamicFriction*normalImpulse)
+}
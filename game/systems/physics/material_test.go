@@ -0,0 +1,128 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCombineMaterials(t *testing.T) {
+	bouncy := components.PhysicsMaterial{Restitution: 1, StaticFriction: 0.2, DynamicFriction: 0.1}
+	sticky := components.PhysicsMaterial{
+		Restitution:     0,
+		StaticFriction:  1,
+		DynamicFriction: 0.8,
+		FrictionCombine: components.CombineMaximum,
+	}
+
+	material := combineMaterials(bouncy, sticky)
+	require.InDelta(t, 0.5, material.restitution, 1e-9)
+	require.InDelta(t, 1, material.staticFriction, 1e-9)
+	require.InDelta(t, 0.8, material.dynamicFriction, 1e-9)
+
+	bouncy.RestitutionCombine = components.CombineMultiply
+	material = combineMaterials(bouncy, sticky)
+	require.Zero(t, material.restitution)
+}
+
+func TestPhysicsMaterial_TOML(t *testing.T) {
+	var material components.PhysicsMaterial
+	material.Init()
+
+	_, err := toml.Decode(`
+Restitution = 0.9
+StaticFriction = 0.6
+DynamicFriction = 0.4
+FrictionCombine = "Maximum"
+`, &material)
+	require.NoError(t, err)
+	require.Equal(t, components.CombineMaximum, material.FrictionCombine)
+	require.Equal(t, components.CombineAverage, material.RestitutionCombine)
+
+	_, err = toml.Decode(`RestitutionCombine = "sum"`, &material)
+	require.Error(t, err)
+}
+
+func TestCollisionResolverSystem_Materials(t *testing.T) {
+	tests := []struct {
+		name     string
+		material components.PhysicsMaterial
+		velocity cp.Vector
+	}{
+		{
+			name:     "bouncy",
+			material: components.PhysicsMaterial{Restitution: 1},
+			velocity: cp.Vector{X: 10, Y: 20},
+		},
+		{
+			name:     "sticky",
+			material: components.PhysicsMaterial{StaticFriction: 1, DynamicFriction: 1},
+			velocity: cp.Vector{X: 0, Y: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			em := ecs.NewEntityManager()
+			systemManager := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+			c := NewCollisionSystem(0)
+			r := NewCollisionResolverSystem(1)
+			systemManager.Add(c)
+			systemManager.Add(r)
+
+			body := testCollider(t, em, true, 0, 9, 10, 10)
+			floor := testCollider(t, em, false, 0, 0, 100, 10)
+
+			for _, entity := range []ecs.EntityID{body, floor} {
+				material, err := ecs.AddComponent[components.PhysicsMaterial](em, entity)
+				require.NoError(t, err)
+				*material = tt.material
+			}
+
+			rigidBody := ecs.MustGetComponent[components.RigidBody](em, body)
+			rigidBody.Velocity = cp.Vector{X: 10, Y: -20}
+
+			require.NoError(t, c.Update())
+			require.NoError(t, r.Update())
+
+			require.InDelta(t, tt.velocity.X, rigidBody.Velocity.X, 1e-9)
+			require.InDelta(t, tt.velocity.Y, rigidBody.Velocity.Y, 1e-9)
+		})
+	}
+}
+
+func TestCollisionResolverSystem_DefaultMaterials(t *testing.T) {
+	em := ecs.NewEntityManager()
+	systemManager := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+	c := NewCollisionSystem(0)
+	r := NewCollisionResolverSystem(1)
+	systemManager.Add(c)
+	systemManager.Add(r)
+
+	// Bodies without a material bounce off each other with 0.8 and off walls with 0.3.
+	left := testCollider(t, em, true, 0, 100, 10, 10)
+	right := testCollider(t, em, true, 9, 100, 10, 10)
+	body := testCollider(t, em, true, 0, 9, 10, 10)
+	testCollider(t, em, false, 0, 0, 100, 10)
+
+	leftBody := ecs.MustGetComponent[components.RigidBody](em, left)
+	rightBody := ecs.MustGetComponent[components.RigidBody](em, right)
+	leftBody.Velocity = cp.Vector{X: 10}
+	rightBody.Velocity = cp.Vector{X: -10}
+
+	rigidBody := ecs.MustGetComponent[components.RigidBody](em, body)
+	rigidBody.Velocity = cp.Vector{Y: -20}
+
+	require.NoError(t, c.Update())
+	require.NoError(t, r.Update())
+
+	require.InDelta(t, -8, leftBody.Velocity.X, 1e-9)
+	require.InDelta(t, 8, rightBody.Velocity.X, 1e-9)
+	require.InDelta(t, 6, rigidBody.Velocity.Y, 1e-9)
+}
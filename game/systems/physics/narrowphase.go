@@ -0,0 +1,89 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/jakecoffman/cp"
+)
+
+// manifold is the minimum translation vector separating two overlapping colliders.
+type manifold struct {
+	// normal points from the first collider towards the second.
+	normal      cp.Vector
+	penetration float64
+}
+
+// collideAABB computes the manifold of two axis aligned boxes.
+// The boxes are separated along the axis with the smallest overlap.
+// Touching boxes overlap with zero penetration.
+func collideAABB(a, b cp.BB) (manifold, bool) {
+	overlapX := min(a.R, b.R) - max(a.L, b.L)
+	overlapY := min(a.T, b.T) - max(a.B, b.B)
+
+	if overlapX < 0 || overlapY < 0 {
+		return manifold{}, false
+	}
+
+	centerA := a.Center()
+	centerB := b.Center()
+
+	if overlapX < overlapY {
+		normal := cp.Vector{X: 1}
+		if centerB.X < centerA.X {
+			normal.X = -1
+		}
+
+		return manifold{normal: normal, penetration: overlapX}, true
+	}
+
+	normal := cp.Vector{Y: 1}
+	if centerB.Y < centerA.Y {
+		normal.Y = -1
+	}
+
+	return manifold{normal: normal, penetration: overlapY}, true
+}
+
+// sweepAABB finds when a box moving by displacement first touches a static box.
+// toi is the fraction of the displacement travelled before impact and the normal
+// points from the moving box towards the static one. Boxes already overlapping at
+// the start are left to collideAABB.
+func sweepAABB(moving cp.BB, displacement cp.Vector, static cp.BB) (toi float64, normal cp.Vector, ok bool) {
+	entryX, exitX, okX := sweepAxis(moving.L, moving.R, displacement.X, static.L, static.R)
+	entryY, exitY, okY := sweepAxis(moving.B, moving.T, displacement.Y, static.B, static.T)
+	if !okX || !okY {
+		return 0, cp.Vector{}, false
+	}
+
+	entry := max(entryX, entryY)
+	exit := min(exitX, exitY)
+	if entry > exit || entry < 0 || entry > 1 {
+		return 0, cp.Vector{}, false
+	}
+
+	if entryX > entryY {
+		normal = cp.Vector{X: math.Copysign(1, displacement.X)}
+	} else {
+		normal = cp.Vector{Y: math.Copysign(1, displacement.Y)}
+	}
+
+	return entry, normal, true
+}
+
+// sweepAxis returns the fractions of the displacement at which the moving interval
+// starts and stops overlapping the static one.
+func sweepAxis(minA, maxA, displacement, minB, maxB float64) (entry, exit float64, ok bool) {
+	if displacement == 0 {
+		if maxA < minB || minA > maxB {
+			return 0, 0, false
+		}
+
+		return math.Inf(-1), math.Inf(1), true
+	}
+
+	if displacement > 0 {
+		return (minB - maxA) / displacement, (maxB - minA) / displacement, true
+	}
+
+	return (maxB - minA) / displacement, (minB - maxA) / displacement, true
+}
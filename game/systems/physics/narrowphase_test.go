@@ -0,0 +1,63 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollideAABB(t *testing.T) {
+	box := cp.BB{L: -5, B: -5, R: 5, T: 5}
+
+	tests := []struct {
+		name        string
+		other       cp.BB
+		ok          bool
+		normal      cp.Vector
+		penetration float64
+	}{
+		{name: "right", other: box.Offset(cp.Vector{X: 8, Y: 1}), ok: true, normal: cp.Vector{X: 1}, penetration: 2},
+		{name: "left", other: box.Offset(cp.Vector{X: -8, Y: 1}), ok: true, normal: cp.Vector{X: -1}, penetration: 2},
+		{name: "above", other: box.Offset(cp.Vector{X: 1, Y: 7}), ok: true, normal: cp.Vector{Y: 1}, penetration: 3},
+		{name: "below", other: box.Offset(cp.Vector{X: 1, Y: -7}), ok: true, normal: cp.Vector{Y: -1}, penetration: 3},
+		{name: "touching", other: box.Offset(cp.Vector{X: 10}), ok: true, normal: cp.Vector{X: 1}, penetration: 0},
+		{name: "separated", other: box.Offset(cp.Vector{X: 11}), ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, ok := collideAABB(box, tt.other)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.normal, m.normal)
+			require.InDelta(t, tt.penetration, m.penetration, 1e-9)
+		})
+	}
+}
+
+func TestSweepAABB(t *testing.T) {
+	box := cp.BB{L: -5, B: -5, R: 5, T: 5}
+	wall := cp.BB{L: 20, B: -50, R: 21, T: 50}
+
+	toi, normal, ok := sweepAABB(box, cp.Vector{X: 100}, wall)
+	require.True(t, ok)
+	require.InDelta(t, 0.15, toi, 1e-9)
+	require.Equal(t, cp.Vector{X: 1}, normal)
+
+	// Moving away or stopping short never hits.
+	_, _, ok = sweepAABB(box, cp.Vector{X: -100}, wall)
+	require.False(t, ok)
+	_, _, ok = sweepAABB(box, cp.Vector{X: 10}, wall)
+	require.False(t, ok)
+
+	// Passing above the wall misses it.
+	_, _, ok = sweepAABB(box.Offset(cp.Vector{Y: 60}), cp.Vector{X: 100}, wall)
+	require.False(t, ok)
+
+	// Diagonal hits report the axis that was entered last.
+	floor := cp.BB{L: -50, B: -21, R: 50, T: -20}
+	toi, normal, ok = sweepAABB(box, cp.Vector{X: 10, Y: -100}, floor)
+	require.True(t, ok)
+	require.InDelta(t, 0.15, toi, 1e-9)
+	require.Equal(t, cp.Vector{Y: -1}, normal)
+}
@@ -26,18 +26,24 @@ func NewPhysicsSystem(priority int) *PhysicsSystem {
 }
 
 func (p *PhysicsSystem) Update() error {
+	return p.step(p.Game().DeltaTime())
+}
+
+func (p *PhysicsSystem) step(dt float64) error {
 	em := p.EntityManager()
 
 	// First, apply physics movement
 	for _, entity := range ecs.Query2[components.RigidBody, components.Transform](em) {
 		rigidBody := ecs.MustGetComponent[components.RigidBody](em, entity)
-		transform := ecs.MustGetComponent[components.Transform](em, entity)
+		if rigidBody.Type == components.BodyStatic {
+			continue
+		}
 
-		game := p.Game()
+		transform := ecs.MustGetComponent[components.Transform](em, entity)
 
 		transform.Translate(
-			rigidBody.Velocity.X*game.DeltaTime(),
-			rigidBody.Velocity.Y*game.DeltaTime(),
+			rigidBody.Velocity.X*dt,
+			rigidBody.Velocity.Y*dt,
 		)
 
 		slog.Debug("Physics.Update",
@@ -74,38 +80,72 @@ func (cr *CollisionResolverSystem) Update() error {
 	// Handle all collision responses
 	for _, entity := range ecs.Query[components.Collision](em) {
 		collision := ecs.MustGetComponent[components.Collision](em, entity)
-		otherEntity := collision.Entity
 
-		// Get components for both entities
-		transform1, hasTransform1 := ecs.GetComponent[components.Transform](em, entity)
-		transform2, hasTransform2 := ecs.GetComponent[components.Transform](em, otherEntity)
+		for _, contact := range collision.Contacts {
+			// Both entities list the contact, resolve it once from the lower entity
+			if contact.Entity < entity {
+				continue
+			}
 
-		if !hasTransform1 || !hasTransform2 {
-			continue
+			cr.resolveContact(em, entity, contact)
 		}
+	}
 
-		rigidbody1, hasRigidBody1 := ecs.GetComponent[components.RigidBody](em, entity)
-		rigidbody2, hasRigidBody2 := ecs.GetComponent[components.RigidBody](em, otherEntity)
+	return nil
+}
 
-		if collision.Penetration <= 0 {
-			continue // No collision
-		}
+func (cr *CollisionResolverSystem) resolveContact(em *ecs.EntityManager, entity ecs.EntityID, contact components.Contact) {
+	otherEntity := contact.Entity
 
-		// Resolve collision based on rigidbody presence
-		if hasRigidBody1 && hasRigidBody2 {
-			// Both have rigidbodies - elastic collision with mass consideration
-			cr.resolveElasticCollision(transform1, rigidbody1, transform2, rigidbody2, collision.Normal, collision.Penetration)
-		} else if hasRigidBody1 && !hasRigidBody2 {
-			// Entity 1 has rigidbody, entity 2 is static
-			cr.resolveStaticCollision(transform1, rigidbody1, collision.Normal, collision.Penetration)
-		} else if !hasRigidBody1 && hasRigidBody2 {
-			// Entity 2 has rigidbody, entity 1 is static
-			cr.resolveStaticCollision(transform2, rigidbody2, cp.Vector{X: -collision.Normal.X, Y: -collision.Normal.Y}, collision.Penetration)
-		}
-		// If neither has rigidbody, no physics response needed
+	// Get components for both entities
+	transform1, hasTransform1 := ecs.GetComponent[components.Transform](em, entity)
+	transform2, hasTransform2 := ecs.GetComponent[components.Transform](em, otherEntity)
+
+	if !hasTransform1 || !hasTransform2 {
+		return
 	}
 
-	return nil
+	rigidbody1, hasRigidBody1 := ecs.GetComponent[components.RigidBody](em, entity)
+	rigidbody2, hasRigidBody2 := ecs.GetComponent[components.RigidBody](em, otherEntity)
+
+	if contact.Penetration <= 0 || contact.Trigger {
+		return // No collision, or a trigger that only reports events
+	}
+
+	material := combineMaterials(materialOf(em, entity), materialOf(em, otherEntity))
+
+	// Only dynamic bodies are pushed, kinematic and static bodies act as immovable surfaces
+	dynamic1 := hasRigidBody1 && rigidbody1.Type == components.BodyDynamic
+	dynamic2 := hasRigidBody2 && rigidbody2.Type == components.BodyDynamic
+
+	// Resolve collision based on which bodies are dynamic
+	if dynamic1 && dynamic2 {
+		// Both are dynamic - elastic collision with mass consideration
+		cr.resolveElasticCollision(transform1, rigidbody1, transform2, rigidbody2, contact.Normal, contact.Penetration, material)
+	} else if dynamic1 {
+		// Entity 2 is immovable
+		cr.resolveStaticCollision(transform1, rigidbody1, contact.Normal, contact.Penetration, material,
+			surfaceVelocity(rigidbody2, hasRigidBody2))
+	} else if dynamic2 {
+		// Entity 1 is immovable
+		cr.resolveStaticCollision(transform2, rigidbody2, contact.Normal.Neg(), contact.Penetration, material,
+			surfaceVelocity(rigidbody1, hasRigidBody1))
+	}
+	// If neither is dynamic, no physics response needed
+}
+
+// surfaceVelocity is the velocity of an immovable body, kinematic bodies carry what they touch.
+func surfaceVelocity(rb *components.RigidBody, hasRigidBody bool) cp.Vector {
+	if !hasRigidBody || rb.Type != components.BodyKinematic {
+		return cp.Vector{}
+	}
+
+	return rb.Velocity
+}
+
+// step resolves the current contacts, the response does not depend on the time step.
+func (cr *CollisionResolverSystem) step(float64) error {
+	return cr.Update()
 }
 
 func (cr *CollisionResolverSystem) Start() error {
@@ -116,7 +156,7 @@ func (cr *CollisionResolverSystem) Teardown() {
 }
 
 func (cr *CollisionResolverSystem) resolveElasticCollision(transform1 *components.Transform, rb1 *components.RigidBody,
-	transform2 *components.Transform, rb2 *components.RigidBody, normal cp.Vector, penetration float64) {
+	transform2 *components.Transform, rb2 *components.RigidBody, normal cp.Vector, penetration float64, material contactMaterial) {
 
 	// Separate objects first
 	totalMass := rb1.Mass + rb2.Mass
@@ -134,25 +174,40 @@ func (cr *CollisionResolverSystem) resolveElasticCollision(transform1 *component
 		Y: rb1.Velocity.Y - rb2.Velocity.Y,
 	}
 
-	// Calculate relative velocity along the normal
+	// Calculate relative velocity along the normal, positive when approaching
 	velocityAlongNormal := relativeVelocity.X*normal.X + relativeVelocity.Y*normal.Y
 
 	// Don't resolve if velocities are separating
-	if velocityAlongNormal > 0 {
+	if velocityAlongNormal <= 0 {
 		return
 	}
 
-	// Restitution (bounciness) - can be made configurable later
-	const restitution = 0.8
+	inverseMass := 0.0
+	if rb1.Mass > 0 && rb2.Mass > 0 {
+		inverseMass = 1/rb1.Mass + 1/rb2.Mass
+	}
 
 	// Calculate impulse scalar
-	impulseScalar := -(1 + restitution) * velocityAlongNormal
-	if rb1.Mass > 0 && rb2.Mass > 0 {
-		impulseScalar /= (1/rb1.Mass + 1/rb2.Mass)
+	impulseScalar := -(1 + material.restitution) * velocityAlongNormal
+	if inverseMass > 0 {
+		impulseScalar /= inverseMass
+	}
+
+	// Friction impulse along the tangent, opposing the sliding velocity
+	tangent := relativeVelocity.Sub(normal.Mult(velocityAlongNormal))
+	tangentSpeed := tangent.Length()
+	frictionScalar := 0.0
+	if tangentSpeed > 0 {
+		frictionScalar = tangentSpeed
+		if inverseMass > 0 {
+			frictionScalar /= inverseMass
+		}
+		frictionScalar = material.friction(frictionScalar, -impulseScalar)
+		tangent = tangent.Mult(1 / tangentSpeed)
 	}
 
 	// Apply impulse
-	impulse := cp.Vector{X: impulseScalar * normal.X, Y: impulseScalar * normal.Y}
+	impulse := normal.Mult(impulseScalar).Sub(tangent.Mult(frictionScalar))
 
 	if rb1.Mass > 0 {
 		rb1.Velocity.X += impulse.X / rb1.Mass
@@ -166,22 +221,28 @@ func (cr *CollisionResolverSystem) resolveElasticCollision(transform1 *component
 }
 
 func (cr *CollisionResolverSystem) resolveStaticCollision(transform *components.Transform, rb *components.RigidBody,
-	normal cp.Vector, penetration float64) {
+	normal cp.Vector, penetration float64, material contactMaterial, surfaceVelocity cp.Vector) {
 
 	// Separate the rigidbody from the static object
 	transform.Translate(-normal.X*penetration, -normal.Y*penetration)
 
-	// Calculate velocity along the normal
-	velocityAlongNormal := rb.Velocity.X*normal.X + rb.Velocity.Y*normal.Y
+	// Calculate velocity relative to the surface along the normal, positive when moving into the static object
+	relativeVelocity := rb.Velocity.Sub(surfaceVelocity)
+	velocityAlongNormal := relativeVelocity.X*normal.X + relativeVelocity.Y*normal.Y
 
 	// Don't resolve if velocity is separating
-	if velocityAlongNormal > 0 {
+	if velocityAlongNormal <= 0 {
 		return
 	}
 
-	// Remove velocity component along the normal (stop at collision)
-	const restitution = 0.3 // Lower restitution for static collisions
+	// Friction slows down the velocity along the surface
+	tangent := relativeVelocity.Sub(normal.Mult(velocityAlongNormal))
+	if tangentSpeed := tangent.Length(); tangentSpeed > 0 {
+		friction := material.friction(tangentSpeed, (1+material.restitution)*velocityAlongNormal)
+		rb.Velocity = rb.Velocity.Sub(tangent.Mult(friction / tangentSpeed))
+	}
 
-	rb.Velocity.X -= (1 + restitution) * velocityAlongNormal * normal.X
-	rb.Velocity.Y -= (1 + restitution) * velocityAlongNormal * normal.Y
+	// Remove velocity component along the normal, bouncing back by the restitution
+	rb.Velocity.X -= (1 + material.restitution) * velocityAlongNormal * normal.X
+	rb.Velocity.Y -= (1 + material.restitution) * velocityAlongNormal * normal.Y
 }
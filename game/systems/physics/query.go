@@ -0,0 +1,197 @@
+package physics
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/samix73/game/helpers"
+)
+
+// QueryFilter selects the colliders a query can report.
+type QueryFilter struct {
+	// Mask is the set of collider categories to report, all categories when zero.
+	Mask components.CollisionLayer
+	// Triggers also reports trigger colliders.
+	Triggers bool
+	// Exclude lists entities that are never reported, such as the entity casting a ray.
+	Exclude []ecs.EntityID
+}
+
+// RaycastHit is where a ray or segment first enters a collider.
+type RaycastHit struct {
+	Entity ecs.EntityID
+	Point  cp.Vector
+	// Normal is the surface normal of the collider at Point, pointing back towards the ray.
+	Normal cp.Vector
+	// Fraction is how far along the segment the hit is, from 0 at the start to 1 at the end.
+	Fraction float64
+}
+
+// Queries answers raycasts, point and area queries over the colliders of a world.
+type Queries struct {
+	em    *ecs.EntityManager
+	index components.SpatialIndex
+}
+
+// NewQueries returns queries over every entity with a Collider and a Transform.
+// In a world with a BroadPhase they share it, and see the colliders where the last
+// collision update found them. Otherwise the colliders are snapshotted, so a system
+// creates one per update and can run any number of queries against it.
+func NewQueries(em *ecs.EntityManager) *Queries {
+	if entity, ok := helpers.First(ecs.Query[components.BroadPhase](em)); ok {
+		return &Queries{em: em, index: ecs.MustGetComponent[components.BroadPhase](em, entity).Index}
+	}
+
+	grid := newSpatialGrid(broadPhaseCellSize)
+	for _, entity := range ecs.Query2[components.Collider, components.Transform](em) {
+		grid.Insert(entity, worldBounds(em, entity))
+	}
+
+	return &Queries{em: em, index: gridSet{grid}}
+}
+
+func (q *Queries) accepts(filter QueryFilter, entity ecs.EntityID) bool {
+	// Colliders removed since the grid was updated are not reported.
+	collider, ok := ecs.GetComponent[components.Collider](q.em, entity)
+	if !ok {
+		return false
+	}
+
+	mask := filter.Mask
+	if mask == components.LayerNone {
+		mask = components.LayerAll
+	}
+
+	category, _ := collider.Layers()
+	if category&mask == 0 || (collider.Trigger && !filter.Triggers) {
+		return false
+	}
+
+	return !slices.Contains(filter.Exclude, entity)
+}
+
+// Raycast casts a ray from origin along direction for at most maxDistance.
+func (q *Queries) Raycast(origin, direction cp.Vector, maxDistance float64, filter QueryFilter) (RaycastHit, bool) {
+	if direction.LengthSq() == 0 || maxDistance <= 0 {
+		return RaycastHit{}, false
+	}
+
+	return q.SegmentCast(origin, origin.Add(direction.Normalize().Mult(maxDistance)), filter)
+}
+
+// SegmentCast returns the first collider hit by the segment from start to end.
+// Colliders that contain start are not reported, so a ray cast from inside a body ignores it.
+func (q *Queries) SegmentCast(start, end cp.Vector, filter QueryFilter) (RaycastHit, bool) {
+	hits := q.SegmentCastAll(start, end, filter)
+	if len(hits) == 0 {
+		return RaycastHit{}, false
+	}
+
+	return hits[0], true
+}
+
+// SegmentCastAll returns every collider hit by the segment from start to end, nearest first.
+func (q *Queries) SegmentCastAll(start, end cp.Vector, filter QueryFilter) []RaycastHit {
+	segment := cp.BB{
+		L: min(start.X, end.X), B: min(start.Y, end.Y),
+		R: max(start.X, end.X), T: max(start.Y, end.Y),
+	}
+
+	hits := make([]RaycastHit, 0)
+	q.index.Query(segment, func(entity ecs.EntityID, bounds cp.BB) {
+		if !q.accepts(filter, entity) {
+			return
+		}
+
+		fraction, normal, ok := segmentAABB(start, end, bounds)
+		if !ok {
+			return
+		}
+
+		hits = append(hits, RaycastHit{
+			Entity:   entity,
+			Point:    start.Lerp(end, fraction),
+			Normal:   normal,
+			Fraction: fraction,
+		})
+	})
+
+	slices.SortFunc(hits, func(a, b RaycastHit) int {
+		if c := cmp.Compare(a.Fraction, b.Fraction); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Entity, b.Entity)
+	})
+
+	return hits
+}
+
+// PointQuery returns the colliders containing point.
+func (q *Queries) PointQuery(point cp.Vector, filter QueryFilter) []ecs.EntityID {
+	return q.OverlapAABB(cp.BB{L: point.X, B: point.Y, R: point.X, T: point.Y}, filter)
+}
+
+// OverlapAABB returns the colliders overlapping bounds, touching edges included.
+func (q *Queries) OverlapAABB(bounds cp.BB, filter QueryFilter) []ecs.EntityID {
+	found := make([]ecs.EntityID, 0)
+	q.index.Query(bounds, func(entity ecs.EntityID, _ cp.BB) {
+		if q.accepts(filter, entity) {
+			found = append(found, entity)
+		}
+	})
+	slices.Sort(found)
+
+	return found
+}
+
+// segmentAABB intersects the segment from start to end with a box using the slab method.
+func segmentAABB(start, end cp.Vector, bounds cp.BB) (fraction float64, normal cp.Vector, ok bool) {
+	if bounds.ContainsVect(start) {
+		return 0, cp.Vector{}, false
+	}
+
+	direction := end.Sub(start)
+	enter, exit := 0.0, 1.0
+
+	axes := []struct {
+		start, direction, min, max float64
+		normal                     cp.Vector
+	}{
+		{start.X, direction.X, bounds.L, bounds.R, cp.Vector{X: 1}},
+		{start.Y, direction.Y, bounds.B, bounds.T, cp.Vector{Y: 1}},
+	}
+
+	for _, axis := range axes {
+		if axis.direction == 0 {
+			if axis.start < axis.min || axis.start > axis.max {
+				return 0, cp.Vector{}, false
+			}
+
+			continue
+		}
+
+		near, far := (axis.min-axis.start)/axis.direction, (axis.max-axis.start)/axis.direction
+		// The entered face faces against the direction of travel.
+		faceNormal := axis.normal.Mult(-math.Copysign(1, axis.direction))
+		if near > far {
+			near, far = far, near
+		}
+
+		if near > enter {
+			enter = near
+			normal = faceNormal
+		}
+		exit = min(exit, far)
+
+		if enter > exit {
+			return 0, cp.Vector{}, false
+		}
+	}
+
+	return enter, normal, true
+}
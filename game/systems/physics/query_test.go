@@ -0,0 +1,101 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueries_Raycast(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	player := testCollider(t, em, true, 0, 0, 10, 10)
+	floor := testCollider(t, em, false, 0, -50, 100, 10)
+	decoration := testCollider(t, em, false, 0, -20, 10, 10)
+	ecs.MustGetComponent[components.Collider](em, decoration).Category = components.LayerDecoration
+
+	q := NewQueries(em)
+
+	// The ray starts inside the player, which is ignored.
+	hit, ok := q.Raycast(cp.Vector{}, cp.Vector{Y: -1}, 100, QueryFilter{})
+	require.True(t, ok)
+	require.Equal(t, decoration, hit.Entity)
+	require.InDelta(t, -15, hit.Point.Y, 1e-9)
+	require.Equal(t, cp.Vector{Y: 1}, hit.Normal)
+
+	// Layers filter what the ray can hit.
+	hit, ok = q.Raycast(cp.Vector{}, cp.Vector{Y: -1}, 100, QueryFilter{Mask: components.LayerAll &^ components.LayerDecoration})
+	require.True(t, ok)
+	require.Equal(t, floor, hit.Entity)
+	require.InDelta(t, -45, hit.Point.Y, 1e-9)
+	require.InDelta(t, 0.45, hit.Fraction, 1e-9)
+
+	_, ok = q.Raycast(cp.Vector{}, cp.Vector{Y: -1}, 10, QueryFilter{})
+	require.False(t, ok)
+
+	// Casting from outside reports the player, unless excluded.
+	hits := q.SegmentCastAll(cp.Vector{X: -100}, cp.Vector{X: 100}, QueryFilter{})
+	require.Len(t, hits, 1)
+	require.Equal(t, player, hits[0].Entity)
+	require.Equal(t, cp.Vector{X: -1}, hits[0].Normal)
+
+	_, ok = q.SegmentCast(cp.Vector{X: -100}, cp.Vector{X: 100}, QueryFilter{Exclude: []ecs.EntityID{player}})
+	require.False(t, ok)
+}
+
+func TestQueries_Overlap(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	a := testCollider(t, em, false, 0, 0, 10, 10)
+	b := testCollider(t, em, false, 8, 0, 10, 10)
+	exit := testCollider(t, em, false, 100, 100, 10, 10)
+	ecs.MustGetComponent[components.Collider](em, exit).Trigger = true
+
+	q := NewQueries(em)
+
+	require.Equal(t, []ecs.EntityID{a, b}, q.PointQuery(cp.Vector{X: 4}, QueryFilter{}))
+	require.Equal(t, []ecs.EntityID{a}, q.PointQuery(cp.Vector{X: -4}, QueryFilter{}))
+	require.Equal(t, []ecs.EntityID{a, b}, q.OverlapAABB(cp.BB{L: -50, B: -50, R: 50, T: 50}, QueryFilter{}))
+
+	// Triggers are only reported when asked for.
+	require.Empty(t, q.PointQuery(cp.Vector{X: 100, Y: 100}, QueryFilter{}))
+	require.Equal(t, []ecs.EntityID{exit}, q.PointQuery(cp.Vector{X: 100, Y: 100}, QueryFilter{Triggers: true}))
+}
+
+func TestQueries_CollisionSystem(t *testing.T) {
+	em, c := testCollisionSystem(t)
+	require.NoError(t, c.Start())
+	t.Cleanup(c.Teardown)
+
+	body := testCollider(t, em, true, 0, 0, 10, 10)
+	wall := testCollider(t, em, false, 100, 0, 10, 10)
+
+	require.NoError(t, c.Update())
+
+	// The queries read the grids of the running CollisionSystem instead of copying them.
+	q := NewQueries(em)
+	require.Equal(t, gridSet{c.static, c.dynamic}, q.index)
+	require.Equal(t, []ecs.EntityID{body, wall}, q.OverlapAABB(cp.BB{L: -200, B: -200, R: 200, T: 200}, QueryFilter{}))
+
+	// Moved colliders are found where the last update saw them.
+	ecs.MustGetComponent[components.Transform](em, wall).SetPosition(-100, 0)
+	require.Equal(t, []ecs.EntityID{wall}, q.PointQuery(cp.Vector{X: 100}, QueryFilter{}))
+	require.NoError(t, c.Update())
+	require.Equal(t, []ecs.EntityID{wall}, q.PointQuery(cp.Vector{X: -100}, QueryFilter{}))
+
+	// Removed colliders are not reported before the next update.
+	require.NoError(t, em.Remove(body))
+	require.Empty(t, q.PointQuery(cp.Vector{}, QueryFilter{}))
+
+	// A second CollisionSystem can't share the world.
+	other := NewCollisionSystem(1)
+	ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{})).Add(other)
+	require.Error(t, other.Start())
+
+	c.Teardown()
+	require.Empty(t, ecs.Query[components.BroadPhase](em))
+	require.Len(t, NewQueries(em).index, 1)
+}
@@ -0,0 +1,318 @@
+package physics
+
+import (
+	"fmt"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+)
+
+var _ ecs.System = (*SpaceSystem)(nil)
+
+func init() {
+	ecs.RegisterSystem(NewSpaceSystem)
+}
+
+// spaceBody links an entity to its Chipmunk body and shape.
+type spaceBody struct {
+	body  *cp.Body
+	shape *cp.Shape
+
+	// position and angle are the last position and rotation synced with the Transform,
+	// used to detect entities moved or rotated outside the physics step.
+	position cp.Vector
+	angle    float64
+	bodyType int
+	// signature is what the shape and mass were built from, used to detect changed colliders.
+	signature shapeSignature
+
+	// gravity is the acceleration applied to the body in the next step.
+	gravity cp.Vector
+}
+
+// updateVelocity integrates velocity with the body's own gravity instead of the space gravity,
+// which allows gravity zones and per body gravity scales.
+func (sb *spaceBody) updateVelocity(body *cp.Body, _ cp.Vector, damping, dt float64) {
+	cp.BodyUpdateVelocity(body, sb.gravity, damping, dt)
+}
+
+// SpaceSystem simulates entities with a Collider and a Transform in a Chipmunk2D space.
+// Entities that also have a RigidBody are simulated with its BodyType, all others are static.
+// Dynamic bodies without mass are simulated as kinematic bodies.
+// Entities with a Joint link the bodies of two other entities with a Chipmunk constraint.
+// The space is stepped with the fixed time step of the world's PhysicsSettings.
+//
+// It is an alternative to FixedStepSystem for worlds that need rotating bodies and
+// stacking, and replaces GravitySystem, PhysicsSystem, CollisionSystem and
+// CollisionResolverSystem in worlds that list it. It does not write Collision contacts
+// or events and has no continuous collision detection for Bullet bodies, so worlds
+// that rely on those keep the collision pipeline of FixedStepSystem.
+type SpaceSystem struct {
+	*ecs.BaseSystem
+
+	space    *cp.Space
+	bodies   map[ecs.EntityID]*spaceBody
+	joints   map[ecs.EntityID]*spaceJoint
+	timestep fixedTimestep
+
+	// seen is reused by syncToSpace to find bodies whose entity is gone.
+	seen map[ecs.EntityID]struct{}
+}
+
+func NewSpaceSystem(priority int) *SpaceSystem {
+	space := cp.NewSpace()
+	space.SetGravity(gravity)
+
+	return &SpaceSystem{
+		BaseSystem: ecs.NewBaseSystem(priority),
+		space:      space,
+		bodies:     make(map[ecs.EntityID]*spaceBody),
+		joints:     make(map[ecs.EntityID]*spaceJoint),
+		seen:       make(map[ecs.EntityID]struct{}),
+	}
+}
+
+// spaceBodyType returns the Chipmunk body type an entity is simulated with.
+func spaceBodyType(em *ecs.EntityManager, entity ecs.EntityID) int {
+	rigidBody, ok := ecs.GetComponent[components.RigidBody](em, entity)
+	switch {
+	case !ok || rigidBody.Type == components.BodyStatic:
+		return cp.BODY_STATIC
+	case rigidBody.Type == components.BodyKinematic || rigidBody.Mass <= 0:
+		return cp.BODY_KINEMATIC
+	default:
+		return cp.BODY_DYNAMIC
+	}
+}
+
+// shapeSignature is the state of an entity that its Chipmunk shape and mass are built from.
+type shapeSignature struct {
+	bounds          cp.BB
+	category, mask  components.CollisionLayer
+	trigger         bool
+	mass            float64
+	restitution     float64
+	dynamicFriction float64
+}
+
+func shapeSignatureOf(em *ecs.EntityManager, entity ecs.EntityID) shapeSignature {
+	collider := ecs.MustGetComponent[components.Collider](em, entity)
+	material := materialOf(em, entity)
+
+	signature := shapeSignature{
+		bounds:          collider.LocalBounds(),
+		trigger:         collider.Trigger,
+		restitution:     material.Restitution,
+		dynamicFriction: material.DynamicFriction,
+	}
+	signature.category, signature.mask = collider.Layers()
+
+	if rigidBody, ok := ecs.GetComponent[components.RigidBody](em, entity); ok {
+		signature.mass = rigidBody.Mass
+	}
+
+	return signature
+}
+
+// newShape creates the shape of a body from its signature.
+func newShape(entity ecs.EntityID, body *cp.Body, signature shapeSignature) *cp.Shape {
+	shape := cp.NewBox2(body, signature.bounds, 0)
+	shape.UserData = entity
+	shape.SetSensor(signature.trigger)
+	shape.SetFilter(cp.NewShapeFilter(cp.NO_GROUP, uint(signature.category), uint(signature.mask)))
+
+	// Chipmunk always multiplies the values of both shapes, combine modes are not supported.
+	shape.SetElasticity(signature.restitution)
+	shape.SetFriction(signature.dynamicFriction)
+
+	return shape
+}
+
+func (s *SpaceSystem) addBody(em *ecs.EntityManager, entity ecs.EntityID) *spaceBody {
+	transform := ecs.MustGetComponent[components.Transform](em, entity)
+	signature := shapeSignatureOf(em, entity)
+
+	var body *cp.Body
+	bodyType := spaceBodyType(em, entity)
+	switch bodyType {
+	case cp.BODY_DYNAMIC:
+		body = cp.NewBody(signature.mass, cp.MomentForBox2(signature.mass, signature.bounds))
+	case cp.BODY_KINEMATIC:
+		body = cp.NewKinematicBody()
+	default:
+		body = cp.NewStaticBody()
+	}
+
+	body.UserData = entity
+	body.SetPosition(transform.Position)
+	body.SetAngle(transform.Rotation)
+
+	shape := newShape(entity, body, signature)
+
+	s.space.AddBody(body)
+	s.space.AddShape(shape)
+
+	sb := &spaceBody{
+		body:      body,
+		shape:     shape,
+		position:  transform.Position,
+		angle:     transform.Rotation,
+		bodyType:  bodyType,
+		signature: signature,
+	}
+	s.bodies[entity] = sb
+
+	if bodyType == cp.BODY_DYNAMIC {
+		body.SetVelocityUpdateFunc(sb.updateVelocity)
+	}
+
+	return sb
+}
+
+func (s *SpaceSystem) removeBody(entity ecs.EntityID) {
+	sb, ok := s.bodies[entity]
+	if !ok {
+		return
+	}
+
+	s.removeJointsOf(entity)
+	s.space.RemoveShape(sb.shape)
+	s.space.RemoveBody(sb.body)
+	delete(s.bodies, entity)
+}
+
+// reindexShapes updates the spatial index after a static body was moved or rotated,
+// static shapes are only indexed when they are added to the space. This is Chipmunk's
+// cpSpaceReindexShapesForBody, which cp does not export, so the shape is added again.
+func (s *SpaceSystem) reindexShapes(sb *spaceBody) {
+	s.space.RemoveShape(sb.shape)
+	s.space.AddShape(sb.shape)
+}
+
+// rebuildShape replaces the shape of a body whose collider, material or mass changed,
+// such as a collider whose slice was loaded after its body was created.
+func (s *SpaceSystem) rebuildShape(entity ecs.EntityID, sb *spaceBody, signature shapeSignature) {
+	s.space.RemoveShape(sb.shape)
+	sb.shape = newShape(entity, sb.body, signature)
+	s.space.AddShape(sb.shape)
+
+	if sb.bodyType == cp.BODY_DYNAMIC {
+		sb.body.SetMass(signature.mass)
+		sb.body.SetMoment(cp.MomentForBox2(signature.mass, signature.bounds))
+	}
+
+	sb.signature = signature
+}
+
+// syncToSpace creates bodies for new entities and pushes ECS state into the space.
+// Bodies whose entity lost its Collider or Transform, or was removed, are cleaned up.
+func (s *SpaceSystem) syncToSpace(em *ecs.EntityManager) {
+	clear(s.seen)
+
+	field := newGravityField(em, gravity)
+	s.space.SetGravity(field.world)
+
+	for _, entity := range ecs.Query2[components.Collider, components.Transform](em) {
+		s.seen[entity] = struct{}{}
+
+		sb, ok := s.bodies[entity]
+		if ok && sb.bodyType != spaceBodyType(em, entity) {
+			s.removeBody(entity)
+			ok = false
+		}
+
+		if !ok {
+			sb = s.addBody(em, entity)
+		} else if signature := shapeSignatureOf(em, entity); signature != sb.signature {
+			s.rebuildShape(entity, sb, signature)
+		}
+
+		transform := ecs.MustGetComponent[components.Transform](em, entity)
+		moved := transform.Position != sb.position || transform.Rotation != sb.angle
+		if transform.Position != sb.position {
+			sb.body.SetPosition(transform.Position)
+			sb.position = transform.Position
+		}
+		if transform.Rotation != sb.angle {
+			sb.body.SetAngle(transform.Rotation)
+			sb.angle = transform.Rotation
+		}
+
+		if sb.bodyType == cp.BODY_STATIC {
+			if moved {
+				s.reindexShapes(sb)
+			}
+
+			continue
+		}
+
+		rigidBody := ecs.MustGetComponent[components.RigidBody](em, entity)
+		sb.body.SetVelocityVector(rigidBody.Velocity)
+		sb.gravity = field.At(transform.Position).Mult(rigidBody.GravityMultiplier())
+	}
+
+	for entity := range s.bodies {
+		if _, ok := s.seen[entity]; !ok {
+			s.removeBody(entity)
+		}
+	}
+}
+
+// syncFromSpace writes simulated positions, rotations and velocities back into the ECS.
+func (s *SpaceSystem) syncFromSpace(em *ecs.EntityManager) {
+	for entity, sb := range s.bodies {
+		if sb.bodyType == cp.BODY_STATIC {
+			continue
+		}
+
+		transform := ecs.MustGetComponent[components.Transform](em, entity)
+		rigidBody := ecs.MustGetComponent[components.RigidBody](em, entity)
+
+		transform.Position = sb.body.Position()
+		transform.Rotation = sb.body.Angle()
+		rigidBody.Velocity = sb.body.Velocity()
+
+		sb.position = transform.Position
+		sb.angle = transform.Rotation
+	}
+}
+
+func (s *SpaceSystem) Update() error {
+	em := s.EntityManager()
+
+	s.syncToSpace(em)
+	if err := s.syncJoints(em); err != nil {
+		return fmt.Errorf("physics.SpaceSystem.Update: %w", err)
+	}
+
+	stepDt, maxSubSteps := stepSettings(em)
+	steps := s.timestep.advance(s.Game().DeltaTime(), stepDt, maxSubSteps)
+
+	for range steps {
+		if err := capturePrevious(em); err != nil {
+			return fmt.Errorf("physics.SpaceSystem.Update: %w", err)
+		}
+
+		s.space.Step(stepDt)
+		s.syncFromSpace(em)
+	}
+
+	interpolate(em, s.timestep.alpha(stepDt))
+
+	return nil
+}
+
+func (s *SpaceSystem) Start() error {
+	return nil
+}
+
+func (s *SpaceSystem) Teardown() {
+	for entity := range s.joints {
+		s.removeJoint(entity)
+	}
+
+	for entity := range s.bodies {
+		s.removeBody(entity)
+	}
+}
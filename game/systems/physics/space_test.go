@@ -0,0 +1,180 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/stretchr/testify/require"
+)
+
+func testColliderEntity(t *testing.T, em *ecs.EntityManager, entityID ecs.EntityID, x, y, width, height float64) {
+	t.Helper()
+
+	transform, err := ecs.AddComponent[components.Transform](em, entityID)
+	require.NoError(t, err)
+	transform.SetPosition(x, y)
+
+	collider, err := ecs.AddComponent[components.Collider](em, entityID)
+	require.NoError(t, err)
+	collider.SetSize(width, height)
+}
+
+func testSpaceSystem(t *testing.T) (*ecs.EntityManager, *SpaceSystem) {
+	t.Helper()
+
+	em := ecs.NewEntityManager()
+	game := ecs.NewGame(&ecs.GameConfig{})
+	systemManager := ecs.NewSystemManager(em, game)
+
+	s := NewSpaceSystem(0)
+	systemManager.Add(s)
+
+	return em, s
+}
+
+func TestSpaceSystem_Update(t *testing.T) {
+	em, s := testSpaceSystem(t)
+
+	body := testRigidbodyEntity(t, em, true)
+	testColliderEntity(t, em, body, 0, 0, 10, 10)
+
+	floor, err := em.NewEntity()
+	require.NoError(t, err)
+	testColliderEntity(t, em, floor, 0, -100, 1000, 10)
+
+	require.NoError(t, s.Update())
+	require.Len(t, s.bodies, 2)
+
+	rigidBody := ecs.MustGetComponent[components.RigidBody](em, body)
+	transform := ecs.MustGetComponent[components.Transform](em, body)
+	require.Negative(t, rigidBody.Velocity.Y)
+
+	for range 240 {
+		require.NoError(t, s.Update())
+	}
+
+	// The body rests on top of the floor instead of falling through it.
+	require.InDelta(t, -90, transform.Position.Y, 1)
+}
+
+func TestSpaceSystem_NoGravity(t *testing.T) {
+	em, s := testSpaceSystem(t)
+
+	body := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, body, 0, 0, 10, 10)
+
+	rigidBody := ecs.MustGetComponent[components.RigidBody](em, body)
+	rigidBody.Velocity.X = 60
+
+	require.NoError(t, s.Update())
+
+	transform := ecs.MustGetComponent[components.Transform](em, body)
+	require.Zero(t, rigidBody.Velocity.Y)
+	require.InDelta(t, 60*s.Game().DeltaTime(), transform.Position.X, 1e-9)
+}
+
+func TestSpaceSystem_RemovesBodies(t *testing.T) {
+	em, s := testSpaceSystem(t)
+
+	wall, err := em.NewEntity()
+	require.NoError(t, err)
+	testColliderEntity(t, em, wall, 0, 0, 10, 10)
+
+	require.NoError(t, s.Update())
+	require.Contains(t, s.bodies, wall)
+	shape := s.bodies[wall].shape
+
+	require.NoError(t, em.Remove(wall))
+	require.NoError(t, s.Update())
+
+	require.NotContains(t, s.bodies, wall)
+	require.False(t, s.space.ContainsShape(shape))
+}
+
+func TestSpaceSystem_GravityZone(t *testing.T) {
+	em, s := testSpaceSystem(t)
+
+	zone, err := em.NewEntity()
+	require.NoError(t, err)
+	gravityZone, err := ecs.AddComponent[components.GravityZone](em, zone)
+	require.NoError(t, err)
+	gravityZone.Bounds = cp.BB{L: -100, B: -100, R: 100, T: 100}
+	gravityZone.Acceleration = cp.Vector{X: 100}
+
+	body := testRigidbodyEntity(t, em, true)
+	testColliderEntity(t, em, body, 0, 0, 10, 10)
+	rigidBody := ecs.MustGetComponent[components.RigidBody](em, body)
+	rigidBody.GravityScale = 0.5
+
+	require.NoError(t, s.Update())
+
+	require.InDelta(t, 50*s.Game().DeltaTime(), rigidBody.Velocity.X, 1e-9)
+	require.Zero(t, rigidBody.Velocity.Y)
+}
+
+func TestSpaceSystem_PushesRotation(t *testing.T) {
+	em, s := testSpaceSystem(t)
+
+	body := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, body, 0, 0, 10, 10)
+
+	require.NoError(t, s.Update())
+
+	// Rotating the entity outside the step rotates its body instead of being overwritten.
+	transform := ecs.MustGetComponent[components.Transform](em, body)
+	transform.Rotation = 1.5
+
+	require.NoError(t, s.Update())
+	require.InDelta(t, 1.5, s.bodies[body].body.Angle(), 1e-9)
+	require.InDelta(t, 1.5, transform.Rotation, 1e-9)
+}
+
+func TestSpaceSystem_MovesStaticColliders(t *testing.T) {
+	em, s := testSpaceSystem(t)
+
+	floor, err := em.NewEntity()
+	require.NoError(t, err)
+	testColliderEntity(t, em, floor, 0, -100, 1000, 10)
+
+	require.NoError(t, s.Update())
+
+	// The floor is moved up, the falling body lands on it at its new position.
+	ecs.MustGetComponent[components.Transform](em, floor).Position.Y = -50
+
+	body := testRigidbodyEntity(t, em, true)
+	testColliderEntity(t, em, body, 0, 0, 10, 10)
+
+	for range 240 {
+		require.NoError(t, s.Update())
+	}
+
+	require.InDelta(t, -40, ecs.MustGetComponent[components.Transform](em, body).Position.Y, 1)
+}
+
+func TestSpaceSystem_RebuildsChangedShapes(t *testing.T) {
+	em, s := testSpaceSystem(t)
+
+	body := testRigidbodyEntity(t, em, false)
+	testColliderEntity(t, em, body, 0, 0, 10, 10)
+
+	require.NoError(t, s.Update())
+	shape := s.bodies[body].shape
+
+	// An unchanged collider keeps its shape.
+	require.NoError(t, s.Update())
+	require.Same(t, shape, s.bodies[body].shape)
+
+	collider := ecs.MustGetComponent[components.Collider](em, body)
+	collider.SetSize(20, 10)
+	collider.Trigger = true
+	ecs.MustGetComponent[components.RigidBody](em, body).Mass = 4
+
+	require.NoError(t, s.Update())
+	sb := s.bodies[body]
+	require.NotSame(t, shape, sb.shape)
+	require.True(t, sb.shape.Sensor())
+	require.InDelta(t, 20, sb.shape.BB().R-sb.shape.BB().L, 1e-9)
+	require.InDelta(t, 4, sb.body.Mass(), 1e-9)
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/samix73/game/keys"
 )
 
+// playerFlapClip is the SpriteAnimation clip played when the player jumps or starts walking.
+const playerFlapClip = "flap"
+
 var _ ecs.System = (*PlayerInputSystem)(nil)
 
 func init() {
@@ -23,20 +26,64 @@ func NewPlayerInputSystem(priority int) *PlayerInputSystem {
 	}
 }
 
-func (p *PlayerInputSystem) Update() error {
-	if !keys.IsPressed(keys.JumpAction) {
-		return nil
+// moveDirection returns the unit direction of the held movement keys, zero when none is held.
+func moveDirection() cp.Vector {
+	var direction cp.Vector
+	if keys.IsHeld(keys.MoveLeftAction) {
+		direction.X--
+	}
+	if keys.IsHeld(keys.MoveRightAction) {
+		direction.X++
+	}
+	if keys.IsHeld(keys.MoveDownAction) {
+		direction.Y--
+	}
+	if keys.IsHeld(keys.MoveUpAction) {
+		direction.Y++
+	}
+
+	if direction == (cp.Vector{}) {
+		return direction
 	}
 
+	return direction.Normalize()
+}
+
+// startedMoving reports whether a movement key was pressed this frame.
+func startedMoving() bool {
+	return keys.IsPressed(keys.MoveUpAction) || keys.IsPressed(keys.MoveDownAction) ||
+		keys.IsPressed(keys.MoveLeftAction) || keys.IsPressed(keys.MoveRightAction)
+}
+
+func (p *PlayerInputSystem) Update() error {
 	em := p.EntityManager()
 
+	jump := keys.IsPressed(keys.JumpAction)
+	direction := moveDirection()
+	started := startedMoving()
+
 	// Find the player entity
 	for _, entity := range ecs.Query2[components.Player, components.RigidBody](em) {
 		rb := ecs.MustGetComponent[components.RigidBody](em, entity)
 
-		// Apply upward impulse for jump
-		jumpForce := cp.Vector{X: 0, Y: 400}
-		rb.ApplyImpulse(jumpForce)
+		flap := jump
+		if movement, ok := ecs.GetComponent[components.PlayerMovement](em, entity); ok {
+			// Players seen from the top walk in the held direction and stop when no key is held
+			rb.Velocity = direction.Mult(movement.Speed)
+			flap = started
+		} else if jump {
+			// Apply upward impulse for jump
+			jumpForce := cp.Vector{X: 0, Y: 400}
+			rb.ApplyImpulse(jumpForce)
+		}
+
+		if !flap {
+			continue
+		}
+
+		if animation, ok := ecs.GetComponent[components.SpriteAnimation](em, entity); ok {
+			animation.Play(playerFlapClip)
+		}
 	}
 
 	return nil
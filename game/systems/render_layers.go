@@ -0,0 +1,137 @@
+package systems
+
+import (
+	"image"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jakecoffman/cp"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/samix73/game/helpers"
+)
+
+// layerView is how a camera projects the sprites of one render layer.
+type layerView struct {
+	projection ebiten.GeoM
+	rotation   float64
+	zoom       float64
+}
+
+// offscreenKey identifies the offscreen image of a layer drawn by a camera.
+type offscreenKey struct {
+	camera ecs.EntityID
+	layer  string
+}
+
+// updateLayers reads the render layers of the world, sorted in drawing order.
+func (c *CameraSystem) updateLayers(em *ecs.EntityManager) {
+	c.layers = c.layers[:0]
+	if settings, ok := helpers.First(ecs.Query[components.RenderLayers](em)); ok {
+		c.layers = append(c.layers, ecs.MustGetComponent[components.RenderLayers](em, settings).Layers...)
+	}
+	if len(c.layers) == 0 {
+		c.layers = append(c.layers, components.DefaultRenderLayers()...)
+	}
+
+	slices.SortStableFunc(c.layers, func(a, b components.RenderLayer) int {
+		return a.Order - b.Order
+	})
+
+	if c.layerIndex == nil {
+		c.layerIndex = make(map[string]int, len(c.layers))
+	}
+	clear(c.layerIndex)
+	for i, layer := range c.layers {
+		c.layerIndex[layer.Name] = i
+	}
+}
+
+// layerOf returns the index of the layer a Renderable is drawn in.
+// Unknown layers fall back to the entities layer, or the first layer without one.
+func (c *CameraSystem) layerOf(name string) int {
+	if i, ok := c.layerIndex[name]; ok {
+		return i
+	}
+
+	return c.layerIndex[components.RenderLayerEntities]
+}
+
+// layerViews computes the projection of every layer for a camera.
+// Layers scroll by their parallax factor, screen space layers are projected by a neutral camera.
+func (c *CameraSystem) layerViews(view *cameraView, camera *components.Camera, position cp.Vector, rotation float64) {
+	view.layers = view.layers[:0]
+
+	for _, layer := range c.layers {
+		if layer.ScreenSpace {
+			neutral := components.Camera{}
+			view.layers = append(view.layers, layerView{
+				projection: neutral.Projection(cp.Vector{}, 0, view.viewport),
+				zoom:       1,
+			})
+
+			continue
+		}
+
+		view.layers = append(view.layers, layerView{
+			projection: camera.Projection(position.Mult(layer.ParallaxFactor()), rotation, view.viewport),
+			rotation:   rotation,
+			zoom:       camera.ZoomFactor(),
+		})
+	}
+}
+
+// offscreenImage returns the image an offscreen layer of a camera is drawn into, cleared and
+// sized to the viewport. Images are kept between frames and only reallocated when the size changes.
+func (c *CameraSystem) offscreenImage(camera ecs.EntityID, layer string, size image.Point) *ebiten.Image {
+	if c.offscreen == nil {
+		c.offscreen = make(map[offscreenKey]*ebiten.Image)
+	}
+
+	key := offscreenKey{camera: camera, layer: layer}
+	img, ok := c.offscreen[key]
+	if ok && img.Bounds().Size() == size {
+		img.Clear()
+		return img
+	}
+
+	if ok {
+		img.Deallocate()
+	}
+
+	img = ebiten.NewImage(size.X, size.Y)
+	c.offscreen[key] = img
+
+	return img
+}
+
+// drawLayer draws the sprites of one layer of a view onto target.
+func (c *CameraSystem) drawLayer(em *ecs.EntityManager, target *ebiten.Image, view *cameraView, layer components.RenderLayer, draws []spriteDraw) {
+	dst := target
+	var offset ebiten.GeoM
+	if layer.Offscreen {
+		dst = c.offscreenImage(view.camera, layer.Name, view.viewport.Size())
+		// Sprites are placed on the target, the offscreen image starts at the viewport corner.
+		offset.Translate(-float64(view.viewport.Min.X), -float64(view.viewport.Min.Y))
+	}
+
+	// Sprites are drawn in order, consecutive sprites from the same atlas page share a draw call.
+	for _, draw := range draws {
+		renderable, ok := ecs.GetComponent[components.Renderable](em, draw.entity)
+		if !ok || renderable.Sprite == nil {
+			continue
+		}
+
+		geoM := draw.geoM
+		geoM.Concat(offset)
+		c.batch.Draw(dst, renderable.Sprite, geoM)
+	}
+	c.batch.Flush(dst)
+
+	if layer.Offscreen {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(view.viewport.Min.X), float64(view.viewport.Min.Y))
+		op.ColorScale.ScaleAlpha(float32(layer.OpacityFactor()))
+		target.DrawImage(dst, op)
+	}
+}
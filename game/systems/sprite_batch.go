@@ -0,0 +1,61 @@
+package systems
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/samix73/game/game/assets"
+)
+
+// triangleTarget is what a spriteBatch draws onto, an *ebiten.Image.
+type triangleTarget interface {
+	DrawTriangles32(vertices []ebiten.Vertex, indices []uint32, img *ebiten.Image, options *ebiten.DrawTrianglesOptions)
+}
+
+// spriteBatch draws consecutive sprites of the same atlas page with a single DrawTriangles call.
+// Its buffers are kept between frames, so drawing does not allocate once they have grown.
+type spriteBatch struct {
+	page     *ebiten.Image
+	vertices []ebiten.Vertex
+	indices  []uint32
+	options  ebiten.DrawTrianglesOptions
+}
+
+// Draw queues a sprite drawn with geoM, flushing the queued sprites first when it is on another page.
+func (b *spriteBatch) Draw(target triangleTarget, sprite *ebiten.Image, geoM ebiten.GeoM) {
+	region, _ := assets.SpriteRegion(sprite)
+	if region.Page != b.page {
+		b.Flush(target)
+		b.page = region.Page
+	}
+
+	bounds := region.Bounds
+	width, height := float64(bounds.Dx()), float64(bounds.Dy())
+	corners := [4][2]float64{{0, 0}, {width, 0}, {width, height}, {0, height}}
+
+	base := uint32(len(b.vertices))
+	for _, corner := range corners {
+		x, y := geoM.Apply(corner[0], corner[1])
+		b.vertices = append(b.vertices, ebiten.Vertex{
+			DstX:   float32(x),
+			DstY:   float32(y),
+			SrcX:   float32(bounds.Min.X) + float32(corner[0]),
+			SrcY:   float32(bounds.Min.Y) + float32(corner[1]),
+			ColorR: 1,
+			ColorG: 1,
+			ColorB: 1,
+			ColorA: 1,
+		})
+	}
+
+	b.indices = append(b.indices, base, base+1, base+2, base, base+2, base+3)
+}
+
+// Flush draws the queued sprites onto target.
+func (b *spriteBatch) Flush(target triangleTarget) {
+	if len(b.indices) > 0 {
+		target.DrawTriangles32(b.vertices, b.indices, b.page, &b.options)
+	}
+
+	b.page = nil
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+}
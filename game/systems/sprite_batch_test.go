@@ -0,0 +1,51 @@
+package systems
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/samix73/game/game/assets"
+	"github.com/stretchr/testify/require"
+)
+
+type drawTrianglesCall struct {
+	page    *ebiten.Image
+	indices int
+}
+
+type recordingTarget struct {
+	calls []drawTrianglesCall
+}
+
+func (r *recordingTarget) DrawTriangles32(_ []ebiten.Vertex, indices []uint32, img *ebiten.Image, _ *ebiten.DrawTrianglesOptions) {
+	r.calls = append(r.calls, drawTrianglesCall{page: img, indices: len(indices)})
+}
+
+func TestSpriteBatch(t *testing.T) {
+	a := assets.AddSprite("test/sprite-batch/a", image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	b := assets.AddSprite("test/sprite-batch/b", image.NewRGBA(image.Rect(0, 0, 8, 2)))
+	page, ok := assets.SpriteRegion(a)
+	require.True(t, ok)
+	other := ebiten.NewImage(4, 4)
+
+	target := &recordingTarget{}
+	batch := &spriteBatch{}
+
+	// Consecutive sprites of the same page are drawn together.
+	batch.Draw(target, a, ebiten.GeoM{})
+	batch.Draw(target, b, ebiten.GeoM{})
+	batch.Draw(target, assets.SubSprite(a, image.Rect(0, 0, 2, 2)), ebiten.GeoM{})
+	require.Empty(t, target.calls)
+
+	// A sprite of another page flushes them first.
+	batch.Draw(target, other, ebiten.GeoM{})
+	require.Equal(t, []drawTrianglesCall{{page: page.Page, indices: 18}}, target.calls)
+
+	batch.Flush(target)
+	require.Equal(t, []drawTrianglesCall{{page: page.Page, indices: 18}, {page: other, indices: 6}}, target.calls)
+
+	// Flushing an empty batch draws nothing.
+	batch.Flush(target)
+	require.Len(t, target.calls, 2)
+}
@@ -0,0 +1,57 @@
+package systems
+
+import (
+	"fmt"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/assets"
+	"github.com/samix73/game/game/components"
+)
+
+var _ ecs.System = (*SpriteSliceSystem)(nil)
+
+func init() {
+	ecs.RegisterSystem(NewSpriteSliceSystem)
+}
+
+// SpriteSliceSystem loads the Aseprite sheets Collider slices refer to and sets the bounds
+// of the slices, once per collider. Worlds list it before the physics systems, so colliders
+// have their bounds before their first step.
+type SpriteSliceSystem struct {
+	*ecs.BaseSystem
+}
+
+func NewSpriteSliceSystem(priority int) *SpriteSliceSystem {
+	return &SpriteSliceSystem{
+		BaseSystem: ecs.NewBaseSystem(priority),
+	}
+}
+
+func (s *SpriteSliceSystem) Update() error {
+	em := s.EntityManager()
+
+	for _, entity := range ecs.Query[components.Collider](em) {
+		slice := &ecs.MustGetComponent[components.Collider](em, entity).Slice
+		if slice.IsZero() || slice.Loaded() {
+			continue
+		}
+
+		sheet, err := assets.GetAseprite(slice.Sheet)
+		if err != nil {
+			return fmt.Errorf("systems.SpriteSliceSystem.Update: entity %d slice %s#%s: %w", entity, slice.Sheet, slice.Name, err)
+		}
+
+		if err := slice.Load(sheet); err != nil {
+			return fmt.Errorf("systems.SpriteSliceSystem.Update: entity %d: %w", entity, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *SpriteSliceSystem) Start() error {
+	return nil
+}
+
+func (s *SpriteSliceSystem) Teardown() {
+}
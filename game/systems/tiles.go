@@ -1,8 +1,11 @@
 package systems
 
 import (
+	"slices"
+
 	"github.com/hajimehoshi/ebiten/v2"
 	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/assets"
 	"github.com/samix73/game/game/components"
 )
 
@@ -12,13 +15,33 @@ func init() {
 	ecs.RegisterSystem(NewTileSystem)
 }
 
+// tileMapState is the TileMap a tile map entity's sprite was last built from.
+type tileMapState struct {
+	width, height, tileSize int
+	atlas                   *ebiten.Image
+	tiles                   []int
+	sprite                  *ebiten.Image
+}
+
+// builtFrom reports whether the state was built from tm into sprite.
+func (s *tileMapState) builtFrom(tm *components.TileMap, sprite *ebiten.Image) bool {
+	return s.sprite == sprite && s.atlas == tm.Atlas &&
+		s.width == tm.Width && s.height == tm.Height && s.tileSize == tm.TileSize &&
+		slices.Equal(s.tiles, tm.Tiles)
+}
+
+// TileSystem draws tile maps into the Renderable sprite of their entity. A tile map is only
+// redrawn when it or the sprite changed since it was last drawn.
 type TileSystem struct {
 	*ecs.BaseSystem
+
+	built map[ecs.EntityID]*tileMapState
 }
 
 func NewTileSystem(priority int) *TileSystem {
 	return &TileSystem{
 		BaseSystem: ecs.NewBaseSystem(priority),
+		built:      make(map[ecs.EntityID]*tileMapState),
 	}
 }
 
@@ -34,8 +57,27 @@ func (t *TileSystem) validateTileMap(tm *components.TileMap) bool {
 	return true
 }
 
-func (t *TileSystem) buildTileSetImage(tm *components.TileMap) *ebiten.Image {
-	img := ebiten.NewImage(tm.Width*tm.TileSize, tm.Height*tm.TileSize)
+// buildTileSetImage draws the tiles into img, reusing the previous image of the tile map
+// when it still has the right size so a rebuild does not allocate a new image.
+func (t *TileSystem) buildTileSetImage(tm *components.TileMap, img *ebiten.Image) *ebiten.Image {
+	width, height := tm.Width*tm.TileSize, tm.Height*tm.TileSize
+
+	reuse := false
+	if img != nil {
+		// Atlas sprites are shared, they are never drawn over.
+		_, packed := assets.SpriteRegion(img)
+		reuse = !packed && img.Bounds().Dx() == width && img.Bounds().Dy() == height
+
+		if !packed && !reuse {
+			img.Deallocate()
+		}
+	}
+
+	if reuse {
+		img.Clear()
+	} else {
+		img = ebiten.NewImage(width, height)
+	}
 
 	for y := 0; y < tm.Height; y++ {
 		for x := 0; x < tm.Width; x++ {
@@ -57,22 +99,47 @@ func (t *TileSystem) buildTileSet(em *ecs.EntityManager, entity ecs.EntityID, tm
 	renderable := ecs.MustGetComponent[components.Renderable](em, entity)
 
 	renderable.Order = tm.Layer
-	renderable.Sprite = t.buildTileSetImage(tm)
+	renderable.Layer = components.RenderLayerTiles
+
+	state, ok := t.built[entity]
+	if ok && state.builtFrom(tm, renderable.Sprite) {
+		return
+	}
+
+	renderable.Sprite = t.buildTileSetImage(tm, renderable.Sprite)
+	t.built[entity] = &tileMapState{
+		width:    tm.Width,
+		height:   tm.Height,
+		tileSize: tm.TileSize,
+		atlas:    tm.Atlas,
+		tiles:    slices.Clone(tm.Tiles),
+		sprite:   renderable.Sprite,
+	}
 }
 
 func (t *TileSystem) Update() error {
 	em := t.EntityManager()
 
-	for _, entity := range ecs.Query2[components.TileMap, components.Renderable](em) {
+	entities := ecs.Query2[components.TileMap, components.Renderable](em)
+	seen := make(map[ecs.EntityID]struct{}, len(entities))
+
+	for _, entity := range entities {
 		tm := ecs.MustGetComponent[components.TileMap](em, entity)
 
 		if !t.validateTileMap(tm) {
 			continue
 		}
 
+		seen[entity] = struct{}{}
 		t.buildTileSet(em, entity, tm)
 	}
 
+	for entity := range t.built {
+		if _, ok := seen[entity]; !ok {
+			delete(t.built, entity)
+		}
+	}
+
 	return nil
 }
 
@@ -81,4 +148,5 @@ func (t *TileSystem) Start() error {
 }
 
 func (t *TileSystem) Teardown() {
+	clear(t.built)
 }
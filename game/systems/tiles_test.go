@@ -0,0 +1,53 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/game/game/components"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTileSystem_RebuildsOnChange(t *testing.T) {
+	em := ecs.NewEntityManager()
+	systemManager := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{ScreenWidth: 800, ScreenHeight: 600}))
+	s := NewTileSystem(0)
+	systemManager.Add(s)
+
+	entity, err := em.NewEntity()
+	require.NoError(t, err)
+	tm, err := ecs.AddComponent[components.TileMap](em, entity)
+	require.NoError(t, err)
+	tm.Width, tm.Height, tm.TileSize = 2, 2, 4
+	tm.Atlas = ebiten.NewImage(8, 8)
+	tm.Init()
+	renderable, err := ecs.AddComponent[components.Renderable](em, entity)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Update())
+	built := s.built[entity]
+	require.NotNil(t, built)
+	require.Same(t, built.sprite, renderable.Sprite)
+
+	// An unchanged tile map is not drawn again.
+	require.NoError(t, s.Update())
+	require.Same(t, built, s.built[entity])
+
+	// Changing a tile or replacing the sprite draws it again.
+	tm.Set(1, 1, 3)
+	require.NoError(t, s.Update())
+	require.NotSame(t, built, s.built[entity])
+	require.Equal(t, []int{-1, -1, -1, 3}, s.built[entity].tiles)
+
+	built = s.built[entity]
+	renderable.Sprite = nil
+	require.NoError(t, s.Update())
+	require.NotSame(t, built, s.built[entity])
+	require.NotNil(t, renderable.Sprite)
+
+	// Removed tile maps are forgotten.
+	require.NoError(t, em.Remove(entity))
+	require.NoError(t, s.Update())
+	require.Empty(t, s.built)
+}
@@ -27,9 +27,40 @@ var (
 		Keys:        []ebiten.Key{ebiten.KeyR},
 		MouseButton: []ebiten.MouseButton{},
 	}
+
+	DebugAction = Action{
+		Keys:        []ebiten.Key{ebiten.KeyF3},
+		MouseButton: []ebiten.MouseButton{},
+	}
+
+	MoveUpAction = Action{
+		Keys:        []ebiten.Key{ebiten.KeyW, ebiten.KeyArrowUp},
+		MouseButton: []ebiten.MouseButton{},
+	}
+
+	MoveDownAction = Action{
+		Keys:        []ebiten.Key{ebiten.KeyS, ebiten.KeyArrowDown},
+		MouseButton: []ebiten.MouseButton{},
+	}
+
+	MoveLeftAction = Action{
+		Keys:        []ebiten.Key{ebiten.KeyA, ebiten.KeyArrowLeft},
+		MouseButton: []ebiten.MouseButton{},
+	}
+
+	MoveRightAction = Action{
+		Keys:        []ebiten.Key{ebiten.KeyD, ebiten.KeyArrowRight},
+		MouseButton: []ebiten.MouseButton{},
+	}
 )
 
 func IsPressed(action Action) bool {
 	return slices.ContainsFunc(action.Keys, inpututil.IsKeyJustPressed) ||
 		slices.ContainsFunc(action.MouseButton, inpututil.IsMouseButtonJustPressed)
 }
+
+// IsHeld reports whether any key or mouse button of the action is held down.
+func IsHeld(action Action) bool {
+	return slices.ContainsFunc(action.Keys, ebiten.IsKeyPressed) ||
+		slices.ContainsFunc(action.MouseButton, ebiten.IsMouseButtonPressed)
+}